@@ -26,12 +26,12 @@ const dataDir = "../data"
 
 // fonts demonstrates how to render text using TTF fonts.
 func fonts() (err error) {
-	// Open a window with the specified dimensions.
+	// Open a window with the specified dimensions and title.
 	const (
 		width  = 640
 		height = 480
 	)
-	win, err := window.Open(width, height)
+	win, err := window.Open(width, height, window.WithTitle("fonts"))
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -18,12 +18,12 @@ func main() {
 }
 
 func example() error {
-	// Open a window with the specified dimensions.
+	// Open a window with the specified dimensions and title.
 	const (
 		width  = 640
 		height = 480
 	)
-	win, err := window.Open(width, height)
+	win, err := window.Open(width, height, window.WithTitle("input_events"))
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -49,5 +49,4 @@ func example() error {
 		// event queue).
 		win.Display()
 	}
-	return nil
 }
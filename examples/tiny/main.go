@@ -24,12 +24,12 @@ func main() {
 const dataDir = "../data"
 
 func example() error {
-	// Open a window with the specified dimensions.
+	// Open a window with the specified dimensions and title.
 	const (
 		width  = 640
 		height = 480
 	)
-	win, err := window.Open(width, height)
+	win, err := window.Open(width, height, window.WithTitle("tiny"))
 	if err != nil {
 		return errors.WithStack(err)
 	}
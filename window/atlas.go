@@ -0,0 +1,374 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+import (
+	"slices"
+	"unsafe"
+
+	"github.com/mewpkg/clog"
+	"github.com/pkg/errors"
+)
+
+// dynamicAtlas is a growable glyph atlas of a dynamic font, into which glyphs
+// are rasterized on first use.
+type dynamicAtlas struct {
+	// Contents of the TTF or OTF font file.
+	data []byte
+	// Base size in pixels of rasterized glyphs.
+	size int
+	// raylib font type (bitmap or SDF).
+	fontType C.int
+	// Padding in pixels around glyphs in the atlas.
+	padding int
+	// Texture filter of the atlas.
+	filter TextureFilter
+	// Maximum width and height in pixels of the atlas.
+	maxSize int
+	// Codepoints of glyphs kept in the atlas on eviction.
+	baseRunes []rune
+
+	// Glyph metrics, and glyph rectangles within the atlas; indexed by glyph
+	// index.
+	glyphs []C.GlyphInfo
+	recs   []C.Rectangle
+	// Rasterized codepoints.
+	rasterized map[rune]bool
+	// Pixels of the atlas (gray-alpha), and atlas dimensions.
+	pix           []byte
+	width, height int
+	// Shelf packing position of the next glyph, and height of the current
+	// shelf.
+	x, y, shelfHeight int
+	// Generation of the atlas, incremented on eviction.
+	gen int
+
+	// underlying raylib atlas texture, and glyph and rectangle arrays (allocated
+	// in C memory).
+	_tex    C.Texture2D
+	_glyphs *C.GlyphInfo
+	_recs   *C.Rectangle
+	// Capacity of the glyph and rectangle arrays, in number of glyphs.
+	_cap int
+}
+
+const (
+	// initAtlasSize specifies the initial width and height in pixels of
+	// dynamic glyph atlases.
+	initAtlasSize = 256
+	// defaultMaxAtlasSize specifies the default maximum width and height in
+	// pixels of dynamic glyph atlases.
+	defaultMaxAtlasSize = 4096
+	// initGlyphCap specifies the initial capacity in number of glyphs of the
+	// glyph and rectangle arrays of dynamic glyph atlases.
+	initGlyphCap = 256
+	// defaultGlyphPadding specifies the padding in pixels around bitmap glyphs
+	// in dynamic glyph atlases.
+	//
+	// ref: FONT_TTF_DEFAULT_CHARS_PADDING=4 (raylib/src/config.h)
+	defaultGlyphPadding = 4
+)
+
+// errAtlasFull is returned when no more glyphs fit into a dynamic glyph atlas
+// of maximum dimensions.
+var errAtlasFull = errors.New("glyph atlas full")
+
+// newDynamicFont returns a new dynamic font based on the given TTF or OTF font
+// file contents, which rasterizes glyphs on first use.
+//
+// Note: a finalizer is registered to unload the font.
+func newDynamicFont(data []byte, size int, opts *FontOptions) (*Font, error) {
+	dyn := &dynamicAtlas{
+		data:       slices.Clone(data),
+		size:       size,
+		fontType:   C.FONT_DEFAULT,
+		padding:    defaultGlyphPadding,
+		filter:     opts.Filter,
+		maxSize:    opts.MaxAtlasSize,
+		rasterized: make(map[rune]bool),
+		pix:        make([]byte, initAtlasSize*initAtlasSize*2),
+		width:      initAtlasSize,
+		height:     initAtlasSize,
+	}
+	if opts.SDF {
+		// Note: SDF glyphs are padded by LoadFontData.
+		dyn.fontType = C.FONT_SDF
+		dyn.padding = 0
+	}
+	if dyn.maxSize == 0 {
+		dyn.maxSize = defaultMaxAtlasSize
+	}
+	// Preload ASCII glyphs (32-126), including the '?' fallback glyph, and the
+	// glyphs specified by the font options.
+	for r := rune(32); r <= 126; r++ {
+		dyn.baseRunes = append(dyn.baseRunes, r)
+	}
+	for _, codepoint := range opts.codepoints() {
+		dyn.baseRunes = append(dyn.baseRunes, rune(codepoint))
+	}
+	font := newFont(C.Font{})
+	font.dyn = dyn
+	if err := font.addGlyphs(dyn.baseRunes); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return font, nil
+}
+
+// ensureGlyphs ensures that the glyphs of the given string are present in the
+// atlas of the dynamic font, evicting glyphs not in use by s if the atlas is
+// full.
+func (font *Font) ensureGlyphs(s string) {
+	dyn := font.dyn
+	runes := []rune(s)
+	err := font.addGlyphs(runes)
+	if errors.Is(err, errAtlasFull) {
+		// Evict all glyphs except for the base glyphs and the glyphs of s.
+		dyn.evict()
+		err = font.addGlyphs(append(slices.Clone(dyn.baseRunes), runes...))
+	}
+	if err != nil {
+		clog.Warnf("unable to rasterize glyphs of dynamic font: %+v", err)
+	}
+}
+
+// addGlyphs rasterizes the given glyphs not yet present in the atlas of the
+// dynamic font, and uploads them to the atlas texture.
+func (font *Font) addGlyphs(runes []rune) error {
+	dyn := font.dyn
+	if !slices.ContainsFunc(runes, func(r rune) bool { return !dyn.rasterized[r] }) {
+		// All glyphs already present.
+		return nil
+	}
+	// Collect codepoints of glyphs to rasterize.
+	var codepoints []C.int
+	added := make(map[rune]bool)
+	for _, r := range runes {
+		if dyn.rasterized[r] || added[r] {
+			continue
+		}
+		added[r] = true
+		codepoints = append(codepoints, C.int(r))
+	}
+	if len(codepoints) == 0 {
+		return nil
+	}
+	// Rasterize glyphs.
+	n := len(codepoints)
+	_data := (*C.uchar)(unsafe.Pointer(&dyn.data[0]))
+	_glyphs := C.LoadFontData(_data, C.int(len(dyn.data)), C.int(dyn.size), &codepoints[0], C.int(n), dyn.fontType)
+	if _glyphs == nil {
+		return errors.Wrap(ErrDecode, "unable to rasterize glyphs")
+	}
+	defer C.UnloadFontData(_glyphs, C.int(n))
+	// Pack glyphs into the atlas.
+	oldWidth, oldHeight := dyn.width, dyn.height
+	var dirty []atlasRegion
+	var err error
+	for _, glyph := range unsafe.Slice(_glyphs, n) {
+		w, h := int(glyph.image.width), int(glyph.image.height)
+		pw, ph := w+2*dyn.padding, h+2*dyn.padding
+		x, y, ok := dyn.pack(pw, ph)
+		for !ok && dyn.grow() {
+			x, y, ok = dyn.pack(pw, ph)
+		}
+		if !ok {
+			err = errors.WithStack(errAtlasFull)
+			break
+		}
+		// Copy glyph pixels into the atlas.
+		if w > 0 && h > 0 {
+			src := unsafe.Slice((*byte)(glyph.image.data), w*h) // grayscale
+			for gy := 0; gy < h; gy++ {
+				for gx := 0; gx < w; gx++ {
+					pos := ((y+dyn.padding+gy)*dyn.width + x + dyn.padding + gx) * 2
+					dyn.pix[pos] = 0xFF           // white
+					dyn.pix[pos+1] = src[gy*w+gx] // alpha
+				}
+			}
+		}
+		dirty = append(dirty, atlasRegion{x: x, y: y, w: pw, h: ph})
+		rec := C.Rectangle{
+			x:      C.float(x + dyn.padding),
+			y:      C.float(y + dyn.padding),
+			width:  C.float(w),
+			height: C.float(h),
+		}
+		glyph.image = C.Image{} // glyph images are not kept.
+		dyn.glyphs = append(dyn.glyphs, glyph)
+		dyn.recs = append(dyn.recs, rec)
+		dyn.rasterized[rune(glyph.value)] = true
+	}
+	// Upload atlas to the GPU.
+	if dyn._tex.id == 0 || dyn.width != oldWidth || dyn.height != oldHeight {
+		if uploadErr := dyn.uploadTexture(); uploadErr != nil {
+			return errors.WithStack(uploadErr)
+		}
+	} else {
+		for _, r := range dirty {
+			dyn.updateTexture(r)
+		}
+	}
+	font.updateDynamicFont()
+	return err
+}
+
+// atlasRegion is a rectangular region of a dynamic glyph atlas.
+type atlasRegion struct {
+	x, y, w, h int
+}
+
+// pack returns the position of a free region of the specified dimensions in
+// the atlas, and a boolean indicating success.
+func (dyn *dynamicAtlas) pack(w, h int) (x, y int, ok bool) {
+	if dyn.x+w > dyn.width {
+		// Start a new shelf.
+		dyn.x = 0
+		dyn.y += dyn.shelfHeight
+		dyn.shelfHeight = 0
+	}
+	if dyn.x+w > dyn.width || dyn.y+h > dyn.height {
+		return 0, 0, false
+	}
+	x, y = dyn.x, dyn.y
+	dyn.x += w
+	dyn.shelfHeight = max(dyn.shelfHeight, h)
+	return x, y, true
+}
+
+// grow doubles the smallest dimension of the atlas, keeping the position of
+// packed glyphs. grow reports whether the atlas was grown, or false if the
+// atlas is of maximum dimensions.
+func (dyn *dynamicAtlas) grow() bool {
+	newWidth, newHeight := dyn.width, dyn.height
+	switch {
+	case newWidth <= newHeight && newWidth < dyn.maxSize:
+		newWidth = min(2*newWidth, dyn.maxSize)
+	case newHeight < dyn.maxSize:
+		newHeight = min(2*newHeight, dyn.maxSize)
+	default:
+		return false
+	}
+	pix := make([]byte, newWidth*newHeight*2)
+	for y := 0; y < dyn.height; y++ {
+		copy(pix[y*newWidth*2:], dyn.pix[y*dyn.width*2:(y+1)*dyn.width*2])
+	}
+	dyn.pix = pix
+	dyn.width, dyn.height = newWidth, newHeight
+	return true
+}
+
+// evict removes all glyphs from the atlas.
+func (dyn *dynamicAtlas) evict() {
+	dyn.glyphs = dyn.glyphs[:0]
+	dyn.recs = dyn.recs[:0]
+	clear(dyn.rasterized)
+	clear(dyn.pix)
+	dyn.x, dyn.y, dyn.shelfHeight = 0, 0, 0
+	dyn.gen++
+	// Re-upload the cleared atlas.
+	dyn.updateTexture(atlasRegion{w: dyn.width, h: dyn.height})
+}
+
+// uploadTexture replaces the atlas texture with the pixels of the atlas.
+func (dyn *dynamicAtlas) uploadTexture() error {
+	_img := C.Image{
+		data:    unsafe.Pointer(&dyn.pix[0]),
+		width:   C.int(dyn.width),
+		height:  C.int(dyn.height),
+		mipmaps: 1,
+		format:  C.PIXELFORMAT_UNCOMPRESSED_GRAY_ALPHA,
+	}
+	_tex := C.LoadTextureFromImage(_img)
+	if _tex.id == 0 {
+		return errors.Wrapf(ErrGPUUpload, "unable to upload %dx%d glyph atlas", dyn.width, dyn.height)
+	}
+	C.SetTextureFilter(_tex, C.int(dyn.filter))
+	if dyn._tex.id != 0 {
+		// Draw pending draw operations using the old atlas texture before
+		// unloading it.
+		C.rlDrawRenderBatchActive()
+		C.UnloadTexture(dyn._tex)
+	}
+	dyn._tex = _tex
+	return nil
+}
+
+// updateTexture uploads the pixels of the given region of the atlas to the
+// atlas texture.
+func (dyn *dynamicAtlas) updateTexture(r atlasRegion) {
+	if dyn._tex.id == 0 || r.w == 0 || r.h == 0 {
+		return
+	}
+	pix := make([]byte, 0, r.w*r.h*2)
+	for y := r.y; y < r.y+r.h; y++ {
+		start := (y*dyn.width + r.x) * 2
+		pix = append(pix, dyn.pix[start:start+r.w*2]...)
+	}
+	// Draw pending draw operations using the previous contents of the atlas
+	// texture before updating it.
+	C.rlDrawRenderBatchActive()
+	_rect := C.Rectangle{
+		x:      C.float(r.x),
+		y:      C.float(r.y),
+		width:  C.float(r.w),
+		height: C.float(r.h),
+	}
+	C.UpdateTextureRec(dyn._tex, _rect, unsafe.Pointer(&pix[0]))
+}
+
+// updateDynamicFont updates the underlying raylib font of the dynamic font to
+// reflect the glyphs of its atlas.
+func (font *Font) updateDynamicFont() {
+	dyn := font.dyn
+	n := len(dyn.glyphs)
+	if n > dyn._cap {
+		// Grow the glyph and rectangle arrays geometrically, to amortize the cost
+		// of reallocation as glyphs are added.
+		newCap := max(n, 2*dyn._cap, initGlyphCap)
+		dyn.freeArrays()
+		dyn._glyphs = (*C.GlyphInfo)(C.MemAlloc(C.uint(newCap * C.sizeof_GlyphInfo)))
+		trackAlloc(unsafe.Pointer(dyn._glyphs), 0)
+		dyn._recs = (*C.Rectangle)(C.MemAlloc(C.uint(newCap * C.sizeof_Rectangle)))
+		trackAlloc(unsafe.Pointer(dyn._recs), 0)
+		dyn._cap = newCap
+	}
+	if n > 0 {
+		copy(unsafe.Slice(dyn._glyphs, n), dyn.glyphs)
+		copy(unsafe.Slice(dyn._recs, n), dyn.recs)
+	}
+	font._font = C.Font{
+		baseSize:     C.int(dyn.size),
+		glyphCount:   C.int(n),
+		glyphPadding: C.int(dyn.padding),
+		texture:      dyn._tex,
+		recs:         dyn._recs,
+		glyphs:       dyn._glyphs,
+	}
+}
+
+// freeArrays frees the glyph and rectangle arrays of the atlas.
+func (dyn *dynamicAtlas) freeArrays() {
+	if dyn._glyphs != nil {
+		untrackAlloc(unsafe.Pointer(dyn._glyphs))
+		C.MemFree(unsafe.Pointer(dyn._glyphs))
+		dyn._glyphs = nil
+	}
+	if dyn._recs != nil {
+		untrackAlloc(unsafe.Pointer(dyn._recs))
+		C.MemFree(unsafe.Pointer(dyn._recs))
+		dyn._recs = nil
+	}
+	dyn._cap = 0
+}
+
+// unload unloads the atlas texture and frees the glyph and rectangle arrays of
+// the atlas.
+func (dyn *dynamicAtlas) unload() {
+	if dyn._tex.id != 0 {
+		C.UnloadTexture(dyn._tex)
+		dyn._tex = C.Texture2D{}
+	}
+	dyn.freeArrays()
+}
@@ -0,0 +1,92 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+// A BlendMode specifies how the colours of drawn images are blended with the
+// colours of the draw target.
+type BlendMode struct {
+	// raylib blend mode.
+	mode C.int
+	// Blend factors and equation of custom blend modes.
+	src, dst BlendFactor
+	eq       BlendEquation
+}
+
+// Blend modes.
+var (
+	// Alpha blending (default).
+	BlendAlpha = BlendMode{mode: C.BLEND_ALPHA}
+	// Additive blending; adds colours.
+	BlendAdditive = BlendMode{mode: C.BLEND_ADDITIVE}
+	// Multiplicative blending; multiplies colours.
+	BlendMultiplied = BlendMode{mode: C.BLEND_MULTIPLIED}
+	// Adds colours, including alpha.
+	BlendAddColors = BlendMode{mode: C.BLEND_ADD_COLORS}
+	// Subtracts colours, including alpha.
+	BlendSubtractColors = BlendMode{mode: C.BLEND_SUBTRACT_COLORS}
+	// Alpha blending of images with premultiplied alpha.
+	BlendAlphaPremultiply = BlendMode{mode: C.BLEND_ALPHA_PREMULTIPLY}
+)
+
+// BlendCustom returns a custom blend mode based on the given source and
+// destination blend factors, and blend equation (as used by glBlendFunc and
+// glBlendEquation).
+func BlendCustom(src, dst BlendFactor, eq BlendEquation) BlendMode {
+	return BlendMode{
+		mode: C.BLEND_CUSTOM,
+		src:  src,
+		dst:  dst,
+		eq:   eq,
+	}
+}
+
+// BlendFactor specifies a blend factor of custom blend modes.
+type BlendFactor int
+
+// Blend factors.
+const (
+	BlendZero                  BlendFactor = C.RL_ZERO
+	BlendOne                   BlendFactor = C.RL_ONE
+	BlendSrcColor              BlendFactor = C.RL_SRC_COLOR
+	BlendOneMinusSrcColor      BlendFactor = C.RL_ONE_MINUS_SRC_COLOR
+	BlendSrcAlpha              BlendFactor = C.RL_SRC_ALPHA
+	BlendOneMinusSrcAlpha      BlendFactor = C.RL_ONE_MINUS_SRC_ALPHA
+	BlendDstAlpha              BlendFactor = C.RL_DST_ALPHA
+	BlendOneMinusDstAlpha      BlendFactor = C.RL_ONE_MINUS_DST_ALPHA
+	BlendDstColor              BlendFactor = C.RL_DST_COLOR
+	BlendOneMinusDstColor      BlendFactor = C.RL_ONE_MINUS_DST_COLOR
+	BlendSrcAlphaSaturate      BlendFactor = C.RL_SRC_ALPHA_SATURATE
+	BlendConstantColor         BlendFactor = C.RL_CONSTANT_COLOR
+	BlendOneMinusConstantColor BlendFactor = C.RL_ONE_MINUS_CONSTANT_COLOR
+	BlendConstantAlpha         BlendFactor = C.RL_CONSTANT_ALPHA
+	BlendOneMinusConstantAlpha BlendFactor = C.RL_ONE_MINUS_CONSTANT_ALPHA
+)
+
+// BlendEquation specifies the blend equation of custom blend modes.
+type BlendEquation int
+
+// Blend equations.
+const (
+	BlendFuncAdd             BlendEquation = C.RL_FUNC_ADD
+	BlendFuncSubtract        BlendEquation = C.RL_FUNC_SUBTRACT
+	BlendFuncReverseSubtract BlendEquation = C.RL_FUNC_REVERSE_SUBTRACT
+	BlendMin                 BlendEquation = C.RL_MIN
+	BlendMax                 BlendEquation = C.RL_MAX
+)
+
+// curBlendMode specifies the blend mode currently in use.
+var curBlendMode = BlendAlpha
+
+// setBlendMode sets the blend mode of subsequent draw operations.
+func setBlendMode(mode BlendMode) {
+	if mode == curBlendMode {
+		return
+	}
+	if mode.mode == C.BLEND_CUSTOM {
+		C.rlSetBlendFactors(C.int(mode.src), C.int(mode.dst), C.int(mode.eq))
+	}
+	C.BeginBlendMode(mode.mode)
+	curBlendMode = mode
+}
@@ -0,0 +1,85 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"image"
+	"math"
+)
+
+// A Camera2D specifies a view transform of draw operations, mapping world
+// coordinates to screen coordinates. Cameras are activated using SetCamera.
+type Camera2D struct {
+	// Target position in world coordinates, which the camera looks at.
+	TargetX, TargetY float64
+	// Offset in screen coordinates of the target position (e.g. the center of
+	// the window).
+	OffsetX, OffsetY float64
+	// Rotation in degrees, clockwise around the target position.
+	Rotation float64
+	// Zoom factor; or 1 if zero.
+	Zoom float64
+}
+
+// ScreenToWorld converts the given point from screen coordinates (e.g. as
+// returned by Window.CursorPos) to world coordinates, rounded down.
+func (cam *Camera2D) ScreenToWorld(pt image.Point) image.Point {
+	_pt := C.GetScreenToWorld2D(vector2FromPoint(pt), cam.raylibCamera())
+	return image.Pt(int(math.Floor(float64(_pt.x))), int(math.Floor(float64(_pt.y))))
+}
+
+// WorldToScreen converts the given point from world coordinates to screen
+// coordinates, rounded down.
+func (cam *Camera2D) WorldToScreen(pt image.Point) image.Point {
+	_pt := C.GetWorldToScreen2D(vector2FromPoint(pt), cam.raylibCamera())
+	return image.Pt(int(math.Floor(float64(_pt.x))), int(math.Floor(float64(_pt.y))))
+}
+
+// raylibCamera converts the camera to the corresponding raylib camera.
+func (cam *Camera2D) raylibCamera() C.Camera2D {
+	zoom := cam.Zoom
+	if zoom == 0 {
+		zoom = 1
+	}
+	return C.Camera2D{
+		offset:   C.Vector2{x: C.float(cam.OffsetX), y: C.float(cam.OffsetY)},
+		target:   C.Vector2{x: C.float(cam.TargetX), y: C.float(cam.TargetY)},
+		rotation: C.float(cam.Rotation),
+		zoom:     C.float(zoom),
+	}
+}
+
+// cameraState specifies the camera of draw operations.
+type cameraState struct {
+	// Render texture ID of the draw target; or 0 if drawing onto the window.
+	target C.uint
+	// Camera is enabled.
+	enabled bool
+	// Camera view transform.
+	cam Camera2D
+}
+
+// curCamera specifies the camera currently in use.
+var curCamera cameraState
+
+// setCamera sets the camera of subsequent draw operations.
+//
+// Note: the camera must be set after binding the draw target, as the view
+// transform is reset when switching draw targets.
+func setCamera(cam cameraState) {
+	// Note: the view transform is reset when switching draw targets.
+	if !cam.enabled && (!curCamera.enabled || cam.target != curCamera.target) {
+		curCamera = cam
+		return
+	}
+	if cam == curCamera {
+		return
+	}
+	if !cam.enabled {
+		C.EndMode2D()
+	} else {
+		C.BeginMode2D(cam.cam.raylibCamera())
+	}
+	curCamera = cam
+}
@@ -0,0 +1,158 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/mewspring/wandi"
+)
+
+// A Canvas is a drawing target, such as a window or an offscreen render target.
+type Canvas interface {
+	// The Drawable interface is implemented by the canvas.
+	wandi.Drawable
+	// Shape primitives are drawn onto the canvas.
+	ShapeDrawer
+	// DrawEx draws the src image onto the canvas starting at the destination
+	// point dp, as transformed and tinted by the given draw options.
+	DrawEx(dp image.Point, src wandi.Image, opts *DrawOptions) error
+	// Clear clears the entire canvas with the given color.
+	Clear(c color.Color)
+	// SetBlendMode sets the blend mode of subsequent draw operations onto the
+	// canvas.
+	SetBlendMode(mode BlendMode)
+	// PushClip restricts subsequent draw operations onto the canvas to the given
+	// clip rectangle, intersected with the current clip rectangle.
+	PushClip(rect image.Rectangle)
+	// PopClip restores the clip rectangle active before the last call to
+	// PushClip.
+	PopClip()
+	// SetCamera sets the camera of subsequent draw operations onto the canvas;
+	// or restores drawing in screen coordinates if nil.
+	SetCamera(cam *Camera2D)
+	// Display finishes drawing what has been rendered so far to the canvas.
+	Display()
+}
+
+// canvas implements draw operations common to windows and render targets.
+type canvas struct {
+	// underlying raylib render texture; or zero value if drawing onto the
+	// window.
+	_rt C.RenderTexture2D
+	// Blend mode of draw operations.
+	blendMode BlendMode
+	// Stack of clip rectangles; the top-most clip rectangle is the intersection
+	// of all pushed clip rectangles.
+	clips []image.Rectangle
+	// Camera of draw operations; or nil if drawing in screen coordinates.
+	cam *Camera2D
+}
+
+// SetBlendMode sets the blend mode of subsequent draw operations onto the
+// canvas. The default blend mode is BlendAlpha.
+func (c *canvas) SetBlendMode(mode BlendMode) {
+	c.blendMode = mode
+}
+
+// PushClip restricts subsequent draw operations onto the canvas to the given
+// clip rectangle, intersected with the current clip rectangle (if any). The
+// previous clip rectangle is restored by PopClip.
+func (c *canvas) PushClip(rect image.Rectangle) {
+	if len(c.clips) > 0 {
+		rect = rect.Intersect(c.clips[len(c.clips)-1])
+	}
+	c.clips = append(c.clips, rect)
+}
+
+// PopClip restores the clip rectangle active before the last call to PushClip.
+func (c *canvas) PopClip() {
+	if len(c.clips) == 0 {
+		panic("unable to pop clip rectangle; clip stack empty")
+	}
+	c.clips = c.clips[:len(c.clips)-1]
+}
+
+// SetCamera sets the camera of subsequent draw operations onto the canvas, thus
+// drawing in world coordinates. Changes to the camera take effect on subsequent
+// draw operations. A nil camera restores drawing in screen coordinates.
+//
+// Note: clip rectangles are specified in screen coordinates.
+func (c *canvas) SetCamera(cam *Camera2D) {
+	c.cam = cam
+}
+
+// bind directs subsequent draw operations to the canvas, using the draw state
+// of the canvas.
+func (c *canvas) bind() {
+	checkMainThread()
+	bindTarget(c._rt)
+	setBlendMode(c.blendMode)
+	clip := clipState{target: c._rt.id}
+	if len(c.clips) > 0 {
+		clip.enabled = true
+		clip.rect = c.clips[len(c.clips)-1]
+	}
+	setClip(clip)
+	camera := cameraState{target: c._rt.id}
+	if c.cam != nil {
+		camera.enabled = true
+		camera.cam = *c.cam
+	}
+	setCamera(camera)
+}
+
+// clipState specifies the clip rectangle of draw operations.
+type clipState struct {
+	// Render texture ID of the draw target; or 0 if drawing onto the window.
+	target C.uint
+	// Clipping is enabled.
+	enabled bool
+	// Clip rectangle.
+	rect image.Rectangle
+}
+
+// curClip specifies the clip rectangle currently in use.
+var curClip clipState
+
+// setClip sets the clip rectangle of subsequent draw operations.
+//
+// Note: the clip rectangle must be set after binding the draw target, as the
+// scissor rectangle is relative to the framebuffer of the draw target.
+func setClip(clip clipState) {
+	if !clip.enabled && !curClip.enabled {
+		curClip = clip
+		return
+	}
+	if clip == curClip {
+		return
+	}
+	if !clip.enabled {
+		C.EndScissorMode()
+	} else {
+		r := clip.rect
+		C.BeginScissorMode(C.int(r.Min.X), C.int(r.Min.Y), C.int(r.Dx()), C.int(r.Dy()))
+	}
+	curClip = clip
+}
+
+// activeTarget specifies the render texture currently drawn onto; or zero
+// value if drawing onto the window.
+var activeTarget C.RenderTexture2D
+
+// bindTarget directs subsequent draw operations to the given render texture, or
+// to the window if _rt is the zero value.
+func bindTarget(_rt C.RenderTexture2D) {
+	if activeTarget.id == _rt.id {
+		return
+	}
+	if activeTarget.id != 0 {
+		C.EndTextureMode()
+	}
+	if _rt.id != 0 {
+		C.BeginTextureMode(_rt)
+	}
+	activeTarget = _rt
+}
@@ -0,0 +1,53 @@
+package window
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"sort"
+	"unsafe"
+
+	"github.com/mewpkg/clog"
+)
+
+// cString returns the given Go string as a C string, allocated in C memory.
+// The C string must be freed using freeCString.
+func cString(s string) *C.char {
+	_s := C.CString(s)
+	// Attribute the allocation to the caller of cString.
+	trackAlloc(unsafe.Pointer(_s), 1)
+	return _s
+}
+
+// freeCString frees the given C string, allocated by cString.
+func freeCString(_s *C.char) {
+	untrackAlloc(unsafe.Pointer(_s))
+	C.free(unsafe.Pointer(_s))
+}
+
+// ReportLeaks logs the outstanding C allocations of the package, grouped by
+// allocation site, and returns the number of outstanding C allocations.
+//
+// Note: C allocations are only tracked when built with the raylibdebug build
+// tag; ReportLeaks reports no allocations otherwise.
+//
+// Example:
+//
+//	// go test -tags raylibdebug
+//	if n := window.ReportLeaks(); n > 0 {
+//		t.Errorf("%d outstanding C allocations", n)
+//	}
+func ReportLeaks() int {
+	sites := outstandingAllocs()
+	var keys []string
+	total := 0
+	for site, n := range sites {
+		keys = append(keys, site)
+		total += n
+	}
+	sort.Strings(keys)
+	for _, site := range keys {
+		clog.Warnf("%d outstanding C allocation(s) at %s", sites[site], site)
+	}
+	return total
+}
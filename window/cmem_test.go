@@ -0,0 +1,64 @@
+//go:build raylibdebug
+
+package window
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+func TestMain(m *testing.M) {
+	// Run tests on another goroutine, while calling functions passed to Do on
+	// the main thread.
+	code := 0
+	Run(func() {
+		code = m.Run()
+	})
+	os.Exit(code)
+}
+
+func TestCStringLeaks(t *testing.T) {
+	_s := cString("foo")
+	_, file, line, _ := runtime.Caller(0)
+	want := fmt.Sprintf("%s:%d", filepath.Base(file), line-1)
+	if got := outstandingAllocs(); got[want] != 1 {
+		t.Errorf("allocation site mismatch; expected %q, got %v", want, got)
+	}
+	if got := unsafe.Slice((*byte)(unsafe.Pointer(_s)), 4); string(got) != "foo\x00" {
+		t.Errorf("C string mismatch; expected %q, got %q", "foo\x00", got)
+	}
+	freeCString(_s)
+	if n := ReportLeaks(); n != 0 {
+		t.Errorf("%d outstanding C allocations", n)
+	}
+}
+
+func TestWindowLeaks(t *testing.T) {
+	// raylib exits the process if the window cannot be initialized, so only
+	// open windows if a display is available.
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		t.Skip("no display available")
+	}
+	var (
+		win *Window
+		err error
+	)
+	Do(func() {
+		win, err = Open(320, 240, WithTitle("leaks"), WithHidden())
+	})
+	if err != nil {
+		t.Fatalf("unable to open window; %v", err)
+	}
+	Do(func() {
+		defer win.Close()
+		win.SetTitle("foo")
+		win.SetTitle("bar")
+	})
+	if n := ReportLeaks(); n != 0 {
+		t.Errorf("%d outstanding C allocations", n)
+	}
+}
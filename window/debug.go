@@ -0,0 +1,56 @@
+//go:build raylibdebug
+
+package window
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// debug specifies whether the package is built in debug mode, which panics
+// when raylib is called from goroutines not running on the main thread, and
+// tracks C allocations of the package.
+const debug = true
+
+var (
+	// allocs maps from outstanding C allocations to allocation site.
+	allocs = make(map[unsafe.Pointer]string)
+	// allocsMutex protects allocs.
+	allocsMutex sync.Mutex
+)
+
+// trackAlloc records the given C allocation. The allocation site is located
+// skip stack frames above the caller of trackAlloc; i.e. 0 identifies the
+// caller of trackAlloc, and 1 the caller of an allocation helper (e.g.
+// cString).
+func trackAlloc(p unsafe.Pointer, skip int) {
+	site := "unknown"
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		site = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	allocsMutex.Lock()
+	defer allocsMutex.Unlock()
+	allocs[p] = site
+}
+
+// untrackAlloc records that the given C allocation has been freed.
+func untrackAlloc(p unsafe.Pointer) {
+	allocsMutex.Lock()
+	defer allocsMutex.Unlock()
+	delete(allocs, p)
+}
+
+// outstandingAllocs returns the number of outstanding C allocations, indexed
+// by allocation site.
+func outstandingAllocs() map[string]int {
+	allocsMutex.Lock()
+	defer allocsMutex.Unlock()
+	sites := make(map[string]int)
+	for _, site := range allocs {
+		sites[site]++
+	}
+	return sites
+}
@@ -0,0 +1,158 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
+)
+
+// DrawOptions specifies how an image is transformed and tinted when drawn using
+// DrawEx. The zero value draws the entire image untransformed.
+type DrawOptions struct {
+	// Source rectangle of the image to draw; or the entire image if empty.
+	Src image.Rectangle
+	// Dimensions of the destination rectangle, stretching the source rectangle
+	// to fit; or the dimensions of the source rectangle multiplied by Scale if
+	// zero.
+	Size image.Point
+	// Scale factor of the source rectangle; or 1 if zero. Ignored if Size is
+	// non-zero.
+	Scale float64
+	// Rotation in degrees, clockwise around Origin.
+	Rotation float64
+	// Pivot point of rotation, relative to the top-left corner of the
+	// destination rectangle.
+	Origin image.Point
+	// Tint colour multiplied with the colour of the image; or white if nil.
+	Tint color.Color
+	// Mirror the image horizontally.
+	FlipH bool
+	// Mirror the image vertically.
+	FlipV bool
+	// Blend mode of the draw operation; or the blend mode of the draw target if
+	// nil.
+	Blend *BlendMode
+}
+
+// drawEx draws the src image onto the active draw target starting at the
+// destination point dp, as transformed and tinted by the given draw options.
+func drawEx(dp image.Point, src wandi.Image, opts *DrawOptions) error {
+	if opts == nil {
+		opts = &DrawOptions{}
+	}
+	sr := opts.Src
+	if sr.Empty() {
+		sr = image.Rect(0, 0, src.Width(), src.Height())
+	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	tint := opts.Tint
+	if tint == nil {
+		tint = color.White
+	}
+	if opts.Blend != nil {
+		setBlendMode(*opts.Blend)
+	}
+	if mtex, ok := src.(*MutableTexture); ok {
+		src = mtex.Texture
+	}
+	_origin := vector2FromPoint(opts.Origin)
+	// The destination rectangle is positioned at the pivot point.
+	dr := C.Rectangle{
+		x:      C.float(dp.X + opts.Origin.X),
+		y:      C.float(dp.Y + opts.Origin.Y),
+		width:  C.float(float64(sr.Dx()) * scale),
+		height: C.float(float64(sr.Dy()) * scale),
+	}
+	if opts.Size != (image.Point{}) {
+		dr.width = C.float(opts.Size.X)
+		dr.height = C.float(opts.Size.Y)
+	}
+	switch src := src.(type) {
+	case *Texture:
+		// Translate source rectangle to the bounds of sub-images.
+		r, minInset, maxInset := src.clipRect(sr)
+		if r.Empty() {
+			return nil
+		}
+		// Crop the destination rectangle by the amount clipped from the source
+		// rectangle, taking flipping into account. The pivot point is kept in
+		// place by offsetting the origin.
+		sx := float64(dr.width) / float64(sr.Dx())
+		sy := float64(dr.height) / float64(sr.Dy())
+		inset := minInset
+		if opts.FlipH {
+			inset.X = maxInset.X
+		}
+		if opts.FlipV {
+			inset.Y = maxInset.Y
+		}
+		_origin.x -= C.float(float64(inset.X) * sx)
+		_origin.y -= C.float(float64(inset.Y) * sy)
+		dr.width = C.float(float64(r.Dx()) * sx)
+		dr.height = C.float(float64(r.Dy()) * sy)
+		_sr := flipRectangle(raylibRectangle(r), opts.FlipH, opts.FlipV)
+		C.DrawTexturePro(src.raylibTexture(), _sr, dr, _origin, C.float(opts.Rotation), raylibColor(tint))
+	case *RenderTarget:
+		if src._rt.id == activeTarget.id {
+			return errors.New("unable to draw render target onto itself")
+		}
+		// Flip source rectangle vertically, as the texture of render targets is
+		// stored bottom-up.
+		_sr := raylibRectangle(sr)
+		_sr.y = C.float(src.Height() - sr.Max.Y)
+		_sr = flipRectangle(_sr, opts.FlipH, !opts.FlipV)
+		C.DrawTexturePro(src._rt.texture, _sr, dr, _origin, C.float(opts.Rotation), raylibColor(tint))
+	case *Text:
+		if opts.FlipH || opts.FlipV || opts.Size != (image.Point{}) || !opts.Src.Empty() {
+			return errors.New("support for flipping, stretching and source rectangles of text not yet implemented")
+		}
+		_pos := C.Vector2{x: dr.x, y: dr.y}
+		c := mulColor(src.c, tint)
+		drawText(src, _pos, _origin, opts.Rotation, scale, c)
+	case *RichText:
+		if opts.FlipH || opts.FlipV || opts.Size != (image.Point{}) || !opts.Src.Empty() {
+			return errors.New("support for flipping, stretching and source rectangles of rich text not yet implemented")
+		}
+		_pos := C.Vector2{x: dr.x, y: dr.y}
+		drawRichText(src, _pos, _origin, opts.Rotation, scale, tint)
+	default:
+		panic(fmt.Errorf("support for image format %T not yet implemented", src))
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// flipRectangle returns the given raylib source rectangle mirrored horizontally
+// and vertically based on the values of flipH and flipV, respectively.
+func flipRectangle(rect C.Rectangle, flipH, flipV bool) C.Rectangle {
+	// Note: raylib mirrors source rectangles of negative width or height.
+	if flipH {
+		rect.width = -rect.width
+	}
+	if flipV {
+		rect.height = -rect.height
+	}
+	return rect
+}
+
+// mulColor returns the component-wise product of the given colours.
+func mulColor(c1, c2 color.Color) color.Color {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+	return color.RGBA{
+		R: uint8(r1 * r2 / 0xFFFF >> 8),
+		G: uint8(g1 * g2 / 0xFFFF >> 8),
+		B: uint8(b1 * b2 / 0xFFFF >> 8),
+		A: uint8(a1 * a2 / 0xFFFF >> 8),
+	}
+}
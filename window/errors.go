@@ -0,0 +1,67 @@
+package window
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned when a file to be loaded does not exist.
+	ErrNotFound = errors.New("file not found")
+	// ErrDecode is returned when the contents of a file could not be decoded
+	// (e.g. corrupt or unsupported image or font file).
+	ErrDecode = errors.New("unable to decode file")
+	// ErrGPUUpload is returned when image data could not be uploaded to the
+	// GPU.
+	ErrGPUUpload = errors.New("unable to upload to GPU")
+	// ErrCompile is returned when a shader program could not be compiled or
+	// linked.
+	ErrCompile = errors.New("unable to compile shader")
+	// ErrInit is returned when the window could not be initialized.
+	ErrInit = errors.New("unable to initialize window")
+)
+
+// A CompileError reports the compile log of a shader program which could not
+// be compiled or linked. It wraps ErrCompile.
+type CompileError struct {
+	// Compile log of the shader program.
+	Log string
+}
+
+// Error returns a string representation of the compile error.
+func (e *CompileError) Error() string {
+	if len(e.Log) == 0 {
+		return ErrCompile.Error()
+	}
+	return ErrCompile.Error() + ":\n" + e.Log
+}
+
+// Unwrap returns ErrCompile.
+func (e *CompileError) Unwrap() error {
+	return ErrCompile
+}
+
+// ### [ Helper functions ] ####################################################
+
+// checkFile returns an error if the given file does not exist, is a directory
+// or is not readable.
+func checkFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrapf(ErrNotFound, "unable to open %q", path)
+		}
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	// Note: directories may be opened, but not loaded.
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if fi.IsDir() {
+		return errors.Wrapf(ErrNotFound, "unable to open %q; is a directory", path)
+	}
+	return nil
+}
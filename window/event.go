@@ -29,7 +29,7 @@ func init() {
 	}
 }
 
-// eventQueue holds pending input events.
+// eventQueue holds pending input and window events.
 var eventQueue = deque.New[we.Event]()
 
 // PollEvent returns a pending event from the event queue or nil if the queue
@@ -38,6 +38,7 @@ var eventQueue = deque.New[we.Event]()
 // Note: the event queue is populated once per frame upon call to
 // Window.Display.
 func (*Window) PollEvent() we.Event {
+	checkMainThread()
 	if eventQueue.Len() > 0 {
 		e := eventQueue.PopFront()
 		return e
@@ -46,6 +47,83 @@ func (*Window) PollEvent() we.Event {
 	return nil
 }
 
+// --- [ window events ] -------------------------------------------------------
+
+// A Focus event is triggered when the window gains or loses input focus.
+type Focus bool
+
+// String returns a string representation of the focus event.
+func (focus Focus) String() string {
+	if focus {
+		return "gained"
+	}
+	return "lost"
+}
+
+// A Minimize event is triggered when the window is minimized or restored from
+// being minimized.
+type Minimize bool
+
+// String returns a string representation of the minimize event.
+func (minimize Minimize) String() string {
+	if minimize {
+		return "minimized"
+	}
+	return "restored"
+}
+
+// A Maximize event is triggered when the window is maximized or restored from
+// being maximized.
+type Maximize bool
+
+// String returns a string representation of the maximize event.
+func (maximize Maximize) String() string {
+	if maximize {
+		return "maximized"
+	}
+	return "restored"
+}
+
+// SetCloseKey sets the keyboard key used to request the window to close, thus
+// triggering a we.Close event. The close key is we.KeyEscape by default. A
+// close key of 0 disables closing the window using the keyboard.
+func (*Window) SetCloseKey(key we.Key) {
+	checkMainThread()
+	if key == 0 {
+		C.SetExitKey(C.KEY_NULL)
+		return
+	}
+	raylibKey, ok := raylibKeyFromKey[key]
+	if !ok {
+		clog.Warnf("support for close key %v using raylib not yet implemented", key)
+		return
+	}
+	C.SetExitKey(raylibKey)
+}
+
+// windowState tracks the state of the window at the previous frame.
+type windowState struct {
+	// Window has input focus.
+	focused bool
+	// Window is minimized.
+	minimized bool
+	// Window is maximized.
+	maximized bool
+}
+
+// getWindowState returns the current state of the window.
+func getWindowState() windowState {
+	return windowState{
+		focused:   bool(C.IsWindowFocused()),
+		minimized: bool(C.IsWindowMinimized()),
+		maximized: bool(C.IsWindowMaximized()),
+	}
+}
+
+// prevWindowState tracks the state of the window at the previous frame.
+// Initialized by Open.
+var prevWindowState windowState
+
 // --- [ keyboard modifier ] ---------------------------------------------------
 
 // getModState returns a bitfield with the current state of the keyboard
@@ -233,18 +311,80 @@ var (
 		we.ButtonLeft:   C.MOUSE_BUTTON_LEFT,
 		we.ButtonRight:  C.MOUSE_BUTTON_RIGHT,
 		we.ButtonMiddle: C.MOUSE_BUTTON_MIDDLE,
+		we.Button4:      C.MOUSE_BUTTON_SIDE,
+		we.Button5:      C.MOUSE_BUTTON_EXTRA,
+		we.Button6:      C.MOUSE_BUTTON_FORWARD,
+		we.Button7:      C.MOUSE_BUTTON_BACK,
 	}
 )
 
+// dragState tracks the drag state of a held mouse button.
+type dragState struct {
+	// Position of the mouse cursor when the button was pressed.
+	start image.Point
+	// Mouse cursor has moved beyond the drag threshold since the button was
+	// pressed.
+	dragging bool
+}
+
+// dragThreshold specifies the distance in pixels the mouse cursor has to move
+// from the press position before mouse drag events are reported.
+var dragThreshold = 3
+
+// SetDragThreshold sets the distance in pixels the mouse cursor has to move
+// while a mouse button is held before we.MouseDrag events are reported for the
+// button, thus ignoring small jitter on mouse clicks. The default drag
+// threshold is 3 pixels.
+//
+// A single we.MouseDrag event is reported per movement, with the Button field
+// holding the dragged mouse buttons combined. The From field holds the position
+// of the mouse cursor when the first of the dragged mouse buttons (in button
+// order; e.g. left before right) was pressed.
+func (*Window) SetDragThreshold(pixels int) {
+	dragThreshold = pixels
+}
+
 // --- [ fill event queue ] ----------------------------------------------------
 
-// fillEventQueue fills the input event queue with input events received since
-// last call to Window.Display.
+// fillEventQueue fills the event queue with input and window events received
+// since last call to Window.Display.
 //
 // Note: fillEventQueue should be invoked at most once each frame (after call to
 // EndDrawing in Window.Display), and must be invoked before polling events
 // through Window.PollEvent.
 func fillEventQueue() {
+	// fill window state events since last frame.
+	winState := getWindowState()
+	if winState.focused != prevWindowState.focused {
+		event := Focus(winState.focused)
+		eventQueue.PushBack(event)
+	}
+	if winState.minimized != prevWindowState.minimized {
+		event := Minimize(winState.minimized)
+		eventQueue.PushBack(event)
+	}
+	if winState.maximized != prevWindowState.maximized {
+		event := Maximize(winState.maximized)
+		eventQueue.PushBack(event)
+	}
+	prevWindowState = winState
+	if C.IsWindowResized() {
+		event := we.Resize{
+			Width:  int(C.GetRenderWidth()),
+			Height: int(C.GetRenderHeight()),
+		}
+		eventQueue.PushBack(event)
+	}
+	// fill window close events since last frame.
+	//
+	// Note: WindowShouldClose reports true when either the close button of the
+	// window or the close key is pressed. It would block while the window is
+	// minimized, if not for the FLAG_WINDOW_ALWAYS_RUN flag set by Open.
+	if C.WindowShouldClose() {
+		event := we.Close{}
+		eventQueue.PushBack(event)
+	}
+
 	mod := getModState()
 	// fill keyboard key press events since last frame.
 	const (
@@ -290,7 +430,7 @@ func fillEventQueue() {
 	// fill mouse button press events since last frame.
 	const (
 		raylibMouseButtonMin raylibMouseButtonType = C.MOUSE_BUTTON_LEFT
-		raylibMouseButtonMax raylibMouseButtonType = C.MOUSE_BUTTON_MIDDLE
+		raylibMouseButtonMax raylibMouseButtonType = C.MOUSE_BUTTON_BACK
 	)
 	_mousePos := C.GetMousePosition()
 	mousePos := image.Pt(int(_mousePos.x), int(_mousePos.y))
@@ -306,6 +446,43 @@ func fillEventQueue() {
 				Mod:    mod,
 			}
 			eventQueue.PushBack(event)
+			// Record press position for mouse drag events.
+			dragStates[button] = &dragState{start: mousePos}
+		}
+	}
+	// fill mouse drag events since last frame; one event per movement, with the
+	// dragged buttons combined.
+	if mousePos != prevMousePos {
+		var buttons we.Button
+		var from image.Point
+		for raylibButton := raylibMouseButtonMin; raylibButton <= raylibMouseButtonMax; raylibButton++ {
+			button := mouseButtonFromRaylibMouseButton[raylibButton]
+			drag, ok := dragStates[button]
+			if !ok {
+				// mouse button not held.
+				continue
+			}
+			if !drag.dragging {
+				// Ignore movement within the drag threshold of the press position.
+				delta := mousePos.Sub(drag.start)
+				if delta.X*delta.X+delta.Y*delta.Y <= dragThreshold*dragThreshold {
+					continue
+				}
+				drag.dragging = true
+			}
+			if buttons == 0 {
+				from = drag.start
+			}
+			buttons |= button
+		}
+		if buttons != 0 {
+			event := we.MouseDrag{
+				Point:  mousePos,
+				From:   from,
+				Button: buttons,
+				Mod:    mod,
+			}
+			eventQueue.PushBack(event)
 		}
 	}
 	// fill mouse button release events since last frame.
@@ -321,13 +498,10 @@ func fillEventQueue() {
 				Mod:    mod,
 			}
 			eventQueue.PushBack(event)
+			delete(dragStates, button)
 		}
 	}
 	// fill mouse movement events since last frame.
-
-	// TODO: implement we.MouseDrag (record mouse position on mouse press and
-	// check if the recorded position differs on mouse release).
-
 	if mousePos != prevMousePos {
 		// Mouse movement detected.
 		event := we.MouseMove{
@@ -339,6 +513,31 @@ func fillEventQueue() {
 
 		prevMousePos = mousePos
 	}
+	// fill mouse wheel scroll events since last frame.
+	//
+	// Note: fractional wheel movement (e.g. from touchpads) is accumulated
+	// until it amounts to at least one whole scroll step.
+	_wheelMove := C.GetMouseWheelMoveV()
+	scrollAcc.X += float64(_wheelMove.x)
+	scrollAcc.Y += float64(_wheelMove.y)
+	if off := int(scrollAcc.X); off != 0 {
+		event := we.ScrollX{
+			Point: mousePos,
+			Off:   off,
+			Mod:   mod,
+		}
+		eventQueue.PushBack(event)
+		scrollAcc.X -= float64(off)
+	}
+	if off := int(scrollAcc.Y); off != 0 {
+		event := we.ScrollY{
+			Point: mousePos,
+			Off:   off,
+			Mod:   mod,
+		}
+		eventQueue.PushBack(event)
+		scrollAcc.Y -= float64(off)
+	}
 	// fill typed rune events since last frame.
 	for {
 		char := C.GetCharPressed()
@@ -348,7 +547,19 @@ func fillEventQueue() {
 		event := we.KeyRune(char)
 		eventQueue.PushBack(event)
 	}
+	// fill touch and gesture events since last frame.
+	fillTouchEvents()
+	// fill gamepad events since last frame.
+	fillGamepadEvents()
 }
 
-// prevMousePos tracks the position of the mouse cursor at the previous frame.
-var prevMousePos image.Point
+var (
+	// prevMousePos tracks the position of the mouse cursor at the previous
+	// frame.
+	prevMousePos image.Point
+	// dragStates tracks the drag state of held mouse buttons.
+	dragStates = make(map[we.Button]*dragState)
+	// scrollAcc accumulates fractional mouse wheel movement not yet reported
+	// as scroll events.
+	scrollAcc struct{ X, Y float64 }
+)
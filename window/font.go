@@ -1,12 +1,18 @@
 package window
 
-// #include <stdlib.h>
 // #include <raylib.h>
 import "C"
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"unicode"
 	"unsafe"
+
+	"github.com/pkg/errors"
 )
 
 // A Font provides glyphs (visual characters) and metrics used for text
@@ -14,22 +20,269 @@ import (
 type Font struct {
 	// underlying raylib font.
 	_font C.Font
+	// Glyph atlas of dynamic fonts; or nil if not dynamic.
+	dyn *dynamicAtlas
+	// Font is the default font, which is owned by raylib.
+	isDefault bool
 }
 
 // LoadFont loads the provided TTF font.
 //
+// The returned error wraps ErrNotFound if the file does not exist, and
+// ErrDecode if the font could not be loaded.
+//
 // Note: a finalizer is registered to unload the font.
 func LoadFont(ttfPath string) (*Font, error) {
-	_ttfPath := C.CString(ttfPath)
-	defer C.free(unsafe.Pointer(_ttfPath))
+	checkMainThread()
+	if err := checkFile(ttfPath); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_ttfPath := cString(ttfPath)
+	defer freeCString(_ttfPath)
 	_font := C.LoadFont(_ttfPath)
-	// TODO: figure out how to check for error.
+	// Note: raylib falls back to the default font on error.
+	if _font.texture.id == 0 || _font.glyphCount == 0 || _font.texture.id == C.GetFontDefault().texture.id {
+		return nil, errors.Wrapf(ErrDecode, "unable to load font %q", ttfPath)
+	}
+	return newFont(_font), nil
+}
+
+// FontOptions specifies how glyphs of fonts loaded using LoadFontEx and
+// LoadFontFromMemory are rasterized. The zero value rasterizes ASCII glyphs
+// (32-126) as a bitmap font of base size 32.
+type FontOptions struct {
+	// Base size in pixels of rasterized glyphs; or 32 if zero.
+	Size int
+	// Codepoints of glyphs to rasterize.
+	Runes []rune
+	// Unicode ranges of glyphs to rasterize, in addition to Runes (e.g.
+	// unicode.Latin, unicode.Cyrillic).
+	Ranges []*unicode.RangeTable
+	// Generate a signed distance field (SDF) font instead of a bitmap font.
+	//
+	// Note: SDF fonts should be drawn using an SDF shader and bilinear texture
+	// filtering.
+	SDF bool
+	// Texture filter of the font atlas.
+	Filter TextureFilter
+	// Rasterize glyphs on first use into a growable font atlas, thus supporting
+	// the full Unicode range of the font without rasterizing every glyph up
+	// front. Glyphs specified by Runes and Ranges are rasterized up front, in
+	// addition to ASCII glyphs (32-126).
+	Dynamic bool
+	// Maximum width and height in pixels of the font atlas of dynamic fonts; or
+	// 4096 if zero. Glyphs not in use are evicted when the font atlas is full.
+	MaxAtlasSize int
+}
+
+// LoadFontEx loads the provided TTF or OTF font, rasterizing glyphs as
+// specified by the given options (or default options if nil).
+//
+// The returned error wraps ErrNotFound if the file does not exist, ErrDecode if
+// the font could not be loaded, and ErrGPUUpload if the font atlas could not be
+// uploaded to the GPU.
+//
+// Note: a finalizer is registered to unload the font.
+func LoadFontEx(path string, opts *FontOptions) (*Font, error) {
+	checkMainThread()
+	if err := checkFile(path); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	font, err := LoadFontFromMemory(data, filepath.Ext(path), opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to load font %q", path)
+	}
+	return font, nil
+}
+
+// LoadFontFromMemory loads a TTF or OTF font from the given file contents (e.g.
+// read from an embed.FS), rasterizing glyphs as specified by the given options
+// (or default options if nil). The file type is specified by the file
+// extension ext (e.g. ".ttf" or ".otf").
+//
+// The returned error wraps ErrDecode if the font could not be loaded, and
+// ErrGPUUpload if the font atlas could not be uploaded to the GPU.
+//
+// Note: a finalizer is registered to unload the font.
+func LoadFontFromMemory(data []byte, ext string, opts *FontOptions) (*Font, error) {
+	checkMainThread()
+	if opts == nil {
+		opts = &FontOptions{}
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	switch strings.ToLower(ext) {
+	case ".ttf", ".otf":
+		// supported font format.
+	default:
+		return nil, errors.Wrapf(ErrDecode, "support for font format %q not yet implemented", ext)
+	}
+	if len(data) == 0 {
+		return nil, errors.Wrap(ErrDecode, "unable to load font from empty file")
+	}
+	size := opts.Size
+	if size == 0 {
+		size = defaultFontSize
+	}
+	if opts.Dynamic {
+		font, err := newDynamicFont(data, size, opts)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return font, nil
+	}
+	// Note: raylib rasterizes ASCII glyphs (32-126) if no codepoints are
+	// specified.
+	codepoints := opts.codepoints()
+	var _codepoints *C.int
+	glyphCount := len(codepoints)
+	if glyphCount > 0 {
+		_codepoints = &codepoints[0]
+	} else {
+		glyphCount = 95
+	}
+	_data := (*C.uchar)(unsafe.Pointer(&data[0]))
+	var _font C.Font
+	if opts.SDF {
+		_glyphs := C.LoadFontData(_data, C.int(len(data)), C.int(size), _codepoints, C.int(glyphCount), C.FONT_SDF)
+		if _glyphs == nil {
+			return nil, errors.Wrap(ErrDecode, "unable to load SDF font data")
+		}
+		_font.baseSize = C.int(size)
+		_font.glyphCount = C.int(glyphCount)
+		_font.glyphs = _glyphs
+		// Note: SDF glyphs are padded by LoadFontData.
+		_atlas := C.GenImageFontAtlas(_glyphs, &_font.recs, C.int(glyphCount), C.int(size), 0, 1)
+		_font.texture = C.LoadTextureFromImage(_atlas)
+		C.UnloadImage(_atlas)
+		if _font.texture.id == 0 {
+			C.UnloadFont(_font)
+			return nil, errors.Wrap(ErrGPUUpload, "unable to load SDF font atlas")
+		}
+	} else {
+		_ext := cString(ext)
+		defer freeCString(_ext)
+		_font = C.LoadFontFromMemory(_ext, _data, C.int(len(data)), C.int(size), _codepoints, C.int(glyphCount))
+		// Note: raylib falls back to the default font on error.
+		if _font.texture.id == 0 || _font.glyphCount == 0 || _font.texture.id == C.GetFontDefault().texture.id {
+			return nil, errors.Wrap(ErrDecode, "unable to load font from memory")
+		}
+	}
+	C.SetTextureFilter(_font.texture, C.int(opts.Filter))
+	return newFont(_font), nil
+}
+
+// codepoints returns the sorted set of codepoints specified by the font
+// options.
+func (opts *FontOptions) codepoints() []C.int {
+	set := make(map[rune]bool)
+	for _, r := range opts.Runes {
+		set[r] = true
+	}
+	for _, table := range opts.Ranges {
+		for _, r16 := range table.R16 {
+			for r := rune(r16.Lo); r <= rune(r16.Hi); r += rune(r16.Stride) {
+				set[r] = true
+			}
+		}
+		for _, r32 := range table.R32 {
+			for r := rune(r32.Lo); r <= rune(r32.Hi); r += rune(r32.Stride) {
+				set[r] = true
+			}
+		}
+	}
+	runes := make([]rune, 0, len(set))
+	for r := range set {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+	codepoints := make([]C.int, len(runes))
+	for i, r := range runes {
+		codepoints[i] = C.int(r)
+	}
+	return codepoints
+}
+
+// defaultFontSize specifies the default base size in pixels of rasterized
+// glyphs.
+//
+// ref: FONT_TTF_DEFAULT_SIZE=32 (raylib/src/config.h)
+const defaultFontSize = 32
+
+// newFont returns a new font based on the given raylib font.
+//
+// Note: a finalizer is registered to unload the font on the main thread.
+func newFont(_font C.Font) *Font {
 	font := &Font{
 		_font: _font,
 	}
-	free := func(obj any) {
-		C.UnloadFont(_font)
+	free := func(font *Font) {
+		_font, dyn := font._font, font.dyn
+		deferRelease(func() {
+			unloadFont(_font, dyn)
+		})
 	}
 	runtime.SetFinalizer(font, free)
-	return font, nil
+	return font
+}
+
+// Free releases the GPU resources of the font. The font must not be used after
+// it has been freed. Freeing a font more than once, or freeing the default
+// font, has no effect.
+//
+// Note: the font is otherwise freed by Window.Display once it has been garbage
+// collected.
+func (font *Font) Free() {
+	checkMainThread()
+	if font.isDefault || (font._font.texture.id == 0 && font.dyn == nil) {
+		return
+	}
+	runtime.SetFinalizer(font, nil)
+	// Draw pending draw operations using the font before unloading it.
+	flushBatch()
+	unloadFont(font._font, font.dyn)
+	font._font = C.Font{}
+	font.dyn = nil
+}
+
+// unloadFont unloads the given raylib font, or the glyph atlas of dynamic fonts
+// if dyn is non-nil.
+func unloadFont(_font C.Font, dyn *dynamicAtlas) {
+	if dyn != nil {
+		dyn.unload()
+		return
+	}
+	C.UnloadFont(_font)
+}
+
+// defaultFont is the default font of raylib; or nil if not yet loaded. The
+// default font is reset by Window.Close, as raylib unloads the default font
+// when the window is closed.
+var defaultFont *Font
+
+// DefaultFont returns the default font, a small bitmap font embedded in raylib.
+// The default font is used by text entries without a font.
+//
+// Note: the default font is available after the window has been opened.
+func DefaultFont() *Font {
+	checkMainThread()
+	if defaultFont != nil {
+		return defaultFont
+	}
+	_font := C.GetFontDefault()
+	font := &Font{
+		_font:     _font,
+		isDefault: true,
+	}
+	if _font.texture.id == 0 {
+		// default font not yet loaded (window not opened).
+		return font
+	}
+	defaultFont = font
+	return defaultFont
 }
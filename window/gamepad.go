@@ -0,0 +1,304 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// maxGamepads specifies the maximum number of gamepads supported by raylib.
+//
+// ref: MAX_GAMEPADS=4 (raylib/src/rcore.c)
+const maxGamepads = 4
+
+// A Gamepad identifies a gamepad (or joystick) by index, in the range [0, 4).
+//
+// Example:
+//
+//	x := window.Gamepad(0).Axis(window.GamepadAxisLeftX)
+type Gamepad int
+
+// Available reports whether the gamepad is connected.
+func (gamepad Gamepad) Available() bool {
+	checkMainThread()
+	return bool(C.IsGamepadAvailable(C.int(gamepad)))
+}
+
+// Name returns the internal name of the gamepad.
+func (gamepad Gamepad) Name() string {
+	checkMainThread()
+	return C.GoString(C.GetGamepadName(C.int(gamepad)))
+}
+
+// Button reports whether the given button of the gamepad is held down.
+func (gamepad Gamepad) Button(button GamepadButton) bool {
+	checkMainThread()
+	return bool(C.IsGamepadButtonDown(C.int(gamepad), C.int(button)))
+}
+
+// Axis returns the movement of the given axis of the gamepad, in the range
+// [-1, 1]. Movement within the gamepad dead zone is reported as 0.
+func (gamepad Gamepad) Axis(axis GamepadAxis) float64 {
+	checkMainThread()
+	value := float64(C.GetGamepadAxisMovement(C.int(gamepad), C.int(axis)))
+	if math.Abs(value) < gamepadDeadZone {
+		return 0
+	}
+	return value
+}
+
+// gamepadDeadZone specifies the absolute axis movement below which gamepad
+// axes are considered at rest.
+var gamepadDeadZone = 0.1
+
+// SetGamepadDeadZone sets the absolute axis movement, in the range [0, 1],
+// below which gamepad axes are considered at rest. The default dead zone is
+// 0.1.
+func (*Window) SetGamepadDeadZone(deadZone float64) {
+	gamepadDeadZone = deadZone
+}
+
+// SetGamepadMappings loads SDL-style gamepad mappings (as used by the
+// SDL_GameControllerDB project), one mapping per line.
+func (*Window) SetGamepadMappings(mappings string) error {
+	checkMainThread()
+	_mappings := cString(mappings)
+	defer freeCString(_mappings)
+	if C.SetGamepadMappings(_mappings) == 0 {
+		return errors.New("unable to load gamepad mappings")
+	}
+	return nil
+}
+
+// --- [ gamepad button ] ------------------------------------------------------
+
+// GamepadButton specifies a gamepad button.
+type GamepadButton int
+
+// Gamepad buttons.
+const (
+	// D-pad up.
+	GamepadButtonLeftFaceUp GamepadButton = C.GAMEPAD_BUTTON_LEFT_FACE_UP
+	// D-pad right.
+	GamepadButtonLeftFaceRight GamepadButton = C.GAMEPAD_BUTTON_LEFT_FACE_RIGHT
+	// D-pad down.
+	GamepadButtonLeftFaceDown GamepadButton = C.GAMEPAD_BUTTON_LEFT_FACE_DOWN
+	// D-pad left.
+	GamepadButtonLeftFaceLeft GamepadButton = C.GAMEPAD_BUTTON_LEFT_FACE_LEFT
+	// Y on Xbox, triangle on PS.
+	GamepadButtonRightFaceUp GamepadButton = C.GAMEPAD_BUTTON_RIGHT_FACE_UP
+	// B on Xbox, circle on PS.
+	GamepadButtonRightFaceRight GamepadButton = C.GAMEPAD_BUTTON_RIGHT_FACE_RIGHT
+	// A on Xbox, cross on PS.
+	GamepadButtonRightFaceDown GamepadButton = C.GAMEPAD_BUTTON_RIGHT_FACE_DOWN
+	// X on Xbox, square on PS.
+	GamepadButtonRightFaceLeft GamepadButton = C.GAMEPAD_BUTTON_RIGHT_FACE_LEFT
+	// Left bumper.
+	GamepadButtonLeftTrigger1 GamepadButton = C.GAMEPAD_BUTTON_LEFT_TRIGGER_1
+	// Left trigger.
+	GamepadButtonLeftTrigger2 GamepadButton = C.GAMEPAD_BUTTON_LEFT_TRIGGER_2
+	// Right bumper.
+	GamepadButtonRightTrigger1 GamepadButton = C.GAMEPAD_BUTTON_RIGHT_TRIGGER_1
+	// Right trigger.
+	GamepadButtonRightTrigger2 GamepadButton = C.GAMEPAD_BUTTON_RIGHT_TRIGGER_2
+	// Back/select.
+	GamepadButtonMiddleLeft GamepadButton = C.GAMEPAD_BUTTON_MIDDLE_LEFT
+	// Guide/home.
+	GamepadButtonMiddle GamepadButton = C.GAMEPAD_BUTTON_MIDDLE
+	// Start.
+	GamepadButtonMiddleRight GamepadButton = C.GAMEPAD_BUTTON_MIDDLE_RIGHT
+	// Left thumbstick press.
+	GamepadButtonLeftThumb GamepadButton = C.GAMEPAD_BUTTON_LEFT_THUMB
+	// Right thumbstick press.
+	GamepadButtonRightThumb GamepadButton = C.GAMEPAD_BUTTON_RIGHT_THUMB
+
+	gamepadButtonFirst = GamepadButtonLeftFaceUp
+	gamepadButtonLast  = GamepadButtonRightThumb
+)
+
+// gamepadButtonNames maps from gamepad button to name.
+var gamepadButtonNames = map[GamepadButton]string{
+	GamepadButtonLeftFaceUp:     "[left face up]",
+	GamepadButtonLeftFaceRight:  "[left face right]",
+	GamepadButtonLeftFaceDown:   "[left face down]",
+	GamepadButtonLeftFaceLeft:   "[left face left]",
+	GamepadButtonRightFaceUp:    "[right face up]",
+	GamepadButtonRightFaceRight: "[right face right]",
+	GamepadButtonRightFaceDown:  "[right face down]",
+	GamepadButtonRightFaceLeft:  "[right face left]",
+	GamepadButtonLeftTrigger1:   "[left trigger 1]",
+	GamepadButtonLeftTrigger2:   "[left trigger 2]",
+	GamepadButtonRightTrigger1:  "[right trigger 1]",
+	GamepadButtonRightTrigger2:  "[right trigger 2]",
+	GamepadButtonMiddleLeft:     "[middle left]",
+	GamepadButtonMiddle:         "[middle]",
+	GamepadButtonMiddleRight:    "[middle right]",
+	GamepadButtonLeftThumb:      "[left thumb]",
+	GamepadButtonRightThumb:     "[right thumb]",
+}
+
+// String returns a string representation of the gamepad button.
+func (button GamepadButton) String() string {
+	if s, ok := gamepadButtonNames[button]; ok {
+		return s
+	}
+	return fmt.Sprintf("[unknown gamepad button: %d]", int(button))
+}
+
+// --- [ gamepad axis ] --------------------------------------------------------
+
+// GamepadAxis specifies a gamepad axis.
+type GamepadAxis int
+
+// Gamepad axes.
+const (
+	// Left stick, horizontal.
+	GamepadAxisLeftX GamepadAxis = C.GAMEPAD_AXIS_LEFT_X
+	// Left stick, vertical.
+	GamepadAxisLeftY GamepadAxis = C.GAMEPAD_AXIS_LEFT_Y
+	// Right stick, horizontal.
+	GamepadAxisRightX GamepadAxis = C.GAMEPAD_AXIS_RIGHT_X
+	// Right stick, vertical.
+	GamepadAxisRightY GamepadAxis = C.GAMEPAD_AXIS_RIGHT_Y
+	// Left trigger pressure, in the range [-1, 1] (-1 at rest).
+	GamepadAxisLeftTrigger GamepadAxis = C.GAMEPAD_AXIS_LEFT_TRIGGER
+	// Right trigger pressure, in the range [-1, 1] (-1 at rest).
+	GamepadAxisRightTrigger GamepadAxis = C.GAMEPAD_AXIS_RIGHT_TRIGGER
+
+	gamepadAxisFirst = GamepadAxisLeftX
+	gamepadAxisLast  = GamepadAxisRightTrigger
+)
+
+// gamepadAxisNames maps from gamepad axis to name.
+var gamepadAxisNames = map[GamepadAxis]string{
+	GamepadAxisLeftX:        "[left x]",
+	GamepadAxisLeftY:        "[left y]",
+	GamepadAxisRightX:       "[right x]",
+	GamepadAxisRightY:       "[right y]",
+	GamepadAxisLeftTrigger:  "[left trigger]",
+	GamepadAxisRightTrigger: "[right trigger]",
+}
+
+// String returns a string representation of the gamepad axis.
+func (axis GamepadAxis) String() string {
+	if s, ok := gamepadAxisNames[axis]; ok {
+		return s
+	}
+	return fmt.Sprintf("[unknown gamepad axis: %d]", int(axis))
+}
+
+// --- [ gamepad events ] ------------------------------------------------------
+
+// A GamepadConnect event is triggered when a gamepad is connected.
+type GamepadConnect struct {
+	Gamepad Gamepad
+	// Internal name of the gamepad.
+	Name string
+}
+
+// A GamepadDisconnect event is triggered when a gamepad is disconnected.
+type GamepadDisconnect struct {
+	Gamepad Gamepad
+}
+
+// A GamepadPress event is triggered when a gamepad button is pressed.
+type GamepadPress struct {
+	Gamepad Gamepad
+	Button  GamepadButton
+}
+
+// A GamepadRelease event is triggered when a gamepad button is released.
+type GamepadRelease struct {
+	Gamepad Gamepad
+	Button  GamepadButton
+}
+
+// A GamepadAxisMove event is triggered when a gamepad axis is moved outside of
+// the gamepad dead zone, or returns to rest.
+type GamepadAxisMove struct {
+	Gamepad Gamepad
+	Axis    GamepadAxis
+	// Axis movement, in the range [-1, 1].
+	Value float64
+	// Axis movement at the previous frame.
+	From float64
+}
+
+// gamepadState tracks the state of a gamepad at the previous frame.
+type gamepadState struct {
+	// Gamepad is connected.
+	available bool
+	// Axis movement, indexed by axis.
+	axes [gamepadAxisLast + 1]float64
+}
+
+// prevGamepadStates tracks the state of gamepads at the previous frame.
+var prevGamepadStates [maxGamepads]gamepadState
+
+// fillGamepadEvents fills the event queue with gamepad events received since
+// last call to Window.Display.
+func fillGamepadEvents() {
+	for gamepad := Gamepad(0); gamepad < maxGamepads; gamepad++ {
+		prev := &prevGamepadStates[gamepad]
+		available := gamepad.Available()
+		// fill gamepad connection events since last frame.
+		if available != prev.available {
+			if available {
+				event := GamepadConnect{
+					Gamepad: gamepad,
+					Name:    gamepad.Name(),
+				}
+				eventQueue.PushBack(event)
+			} else {
+				event := GamepadDisconnect{
+					Gamepad: gamepad,
+				}
+				eventQueue.PushBack(event)
+				prev.axes = [gamepadAxisLast + 1]float64{}
+			}
+			prev.available = available
+		}
+		if !available {
+			continue
+		}
+		// fill gamepad button press events since last frame.
+		for button := gamepadButtonFirst; button <= gamepadButtonLast; button++ {
+			if C.IsGamepadButtonPressed(C.int(gamepad), C.int(button)) {
+				event := GamepadPress{
+					Gamepad: gamepad,
+					Button:  button,
+				}
+				eventQueue.PushBack(event)
+			}
+		}
+		// fill gamepad button release events since last frame.
+		for button := gamepadButtonFirst; button <= gamepadButtonLast; button++ {
+			if C.IsGamepadButtonReleased(C.int(gamepad), C.int(button)) {
+				event := GamepadRelease{
+					Gamepad: gamepad,
+					Button:  button,
+				}
+				eventQueue.PushBack(event)
+			}
+		}
+		// fill gamepad axis movement events since last frame.
+		naxes := GamepadAxis(C.GetGamepadAxisCount(C.int(gamepad)))
+		for axis := gamepadAxisFirst; axis <= gamepadAxisLast && axis < naxes; axis++ {
+			value := gamepad.Axis(axis)
+			if value != prev.axes[axis] {
+				event := GamepadAxisMove{
+					Gamepad: gamepad,
+					Axis:    axis,
+					Value:   value,
+					From:    prev.axes[axis],
+				}
+				eventQueue.PushBack(event)
+				prev.axes[axis] = value
+			}
+		}
+	}
+}
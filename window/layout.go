@@ -0,0 +1,289 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+// Align specifies the horizontal alignment of the lines of a text entry.
+type Align int
+
+// Text alignments.
+const (
+	// Align lines to the left edge of the text entry.
+	AlignLeft Align = iota
+	// Center lines within the text entry.
+	AlignCenter
+	// Align lines to the right edge of the text entry.
+	AlignRight
+	// Stretch the spaces between words of wrapped lines to fill the width of the
+	// text entry. The last line of each paragraph is aligned to the left.
+	AlignJustify
+)
+
+// String returns a string representation of the text alignment.
+func (align Align) String() string {
+	switch align {
+	case AlignLeft:
+		return "left"
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	case AlignJustify:
+		return "justify"
+	}
+	return fmt.Sprintf("unknown alignment: %d", int(align))
+}
+
+const (
+	// defaultLineHeight specifies the default line height, relative to the font
+	// size.
+	//
+	// ref: line spacing of DrawTextEx (raylib/src/rtext.c)
+	defaultLineHeight = 1.5
+	// ellipsis is appended to the last line of text entries truncated to a
+	// maximum number of lines.
+	ellipsis = "..."
+)
+
+// textLayout is the layout of a text entry.
+type textLayout struct {
+	// Laid out lines.
+	lines []*textLine
+	// Dimensions of the bounding box of the text entry.
+	width, height float64
+}
+
+// textLine is a laid out line of a text entry.
+type textLine struct {
+	// Characters of the line.
+	runes []rune
+	// Horizontal position of each character, relative to the left edge of the
+	// text entry.
+	xs []float64
+	// Vertical position of the line, relative to the top edge of the text entry.
+	y float64
+	// Width of the line.
+	width float64
+	// The line was wrapped, and may thus be justified.
+	wrapped bool
+}
+
+// layout returns the layout of the text entry, laying out the text if not yet
+// laid out.
+func (text *Text) layout() *textLayout {
+	_font := text.raylibFont()
+	if text.lay != nil {
+		return text.lay
+	}
+	advance := func(r rune) float64 {
+		return glyphAdvance(_font, r, float64(text.fontSize))
+	}
+	text.lay = text.layoutWith(advance)
+	return text.lay
+}
+
+// layoutWith lays out the text entry, using the given function to compute the
+// horizontal advance in pixels of characters, excluding letter spacing.
+func (text *Text) layoutWith(advance func(r rune) float64) *textLayout {
+	lay := &textLayout{}
+	// Split text into paragraphs at explicit newlines, and wrap paragraphs to
+	// the maximum width.
+	for _, paragraph := range strings.Split(text.str, "\n") {
+		lay.lines = append(lay.lines, text.wrap(advance, []rune(paragraph))...)
+	}
+	// Truncate text to the maximum number of lines.
+	if text.maxLines > 0 && len(lay.lines) > text.maxLines {
+		lay.lines = lay.lines[:text.maxLines]
+		last := lay.lines[len(lay.lines)-1]
+		runes := trimSpaceRight(last.runes)
+		for {
+			line := text.newLine(advance, append(slices.Clone(runes), []rune(ellipsis)...))
+			if len(runes) == 0 || text.maxWidth <= 0 || line.width <= float64(text.maxWidth) {
+				*last = *line
+				break
+			}
+			runes = trimSpaceRight(runes[:len(runes)-1])
+		}
+	}
+	// Compute bounding box; lines are aligned within the maximum width, if
+	// specified.
+	lineHeight := text.lineHeight * float64(text.fontSize)
+	for i, line := range lay.lines {
+		line.y = float64(i) * lineHeight
+		lay.width = math.Max(lay.width, line.width)
+	}
+	if text.maxWidth > 0 {
+		lay.width = math.Max(lay.width, float64(text.maxWidth))
+	}
+	lay.height = float64(text.fontSize) + float64(len(lay.lines)-1)*lineHeight
+	// Align lines within the bounding box.
+	for _, line := range lay.lines {
+		text.alignLine(line, lay.width)
+	}
+	return lay
+}
+
+// wrap wraps the given paragraph into lines not exceeding the maximum width of
+// the text entry, breaking lines at spaces if possible.
+func (text *Text) wrap(advance func(r rune) float64, runes []rune) []*textLine {
+	if text.maxWidth <= 0 {
+		return []*textLine{text.newLine(advance, runes)}
+	}
+	var lines []*textLine
+	maxWidth := float64(text.maxWidth)
+	// Trailing spaces are not wrapped onto lines of their own.
+	runes = trimSpaceRight(runes)
+	for {
+		line := text.newLine(advance, runes)
+		if line.width <= maxWidth {
+			lines = append(lines, line)
+			return lines
+		}
+		// Locate the first character exceeding the maximum width.
+		end := 1
+		for end < len(runes) && line.xs[end]+advance(runes[end]) <= maxWidth {
+			end++
+		}
+		// Break the line at the last space before the end, or at the end if the
+		// first word doesn't fit on a line of its own.
+		brk := end
+		for i := min(end, len(runes)-1); i > 0; i-- {
+			if len(trimSpaceRight(runes[:i])) == 0 {
+				// Leading spaces only; the first word doesn't fit.
+				break
+			}
+			if unicode.IsSpace(runes[i]) {
+				brk = i
+				break
+			}
+		}
+		line = text.newLine(advance, trimSpaceRight(runes[:brk]))
+		lines = append(lines, line)
+		runes = trimSpaceLeft(runes[brk:])
+		if len(runes) == 0 {
+			return lines
+		}
+		line.wrapped = true
+	}
+}
+
+// newLine returns a new line of the given characters, positioned from the left
+// edge of the text entry.
+func (text *Text) newLine(advance func(r rune) float64, runes []rune) *textLine {
+	line := &textLine{
+		runes: runes,
+		xs:    make([]float64, len(runes)),
+	}
+	x := 0.0
+	for i, r := range runes {
+		line.xs[i] = x
+		line.width = x + advance(r)
+		x += advance(r) + text.spacing
+	}
+	return line
+}
+
+// alignLine aligns the given line within the specified width.
+func (text *Text) alignLine(line *textLine, width float64) {
+	var dx float64
+	switch text.align {
+	case AlignCenter:
+		dx = (width - line.width) / 2
+	case AlignRight:
+		dx = width - line.width
+	case AlignJustify:
+		if !line.wrapped {
+			break
+		}
+		// Distribute the remaining width evenly between the spaces of the line.
+		nspaces := 0
+		for _, r := range line.runes {
+			if unicode.IsSpace(r) {
+				nspaces++
+			}
+		}
+		if nspaces == 0 {
+			break
+		}
+		extra := (width - line.width) / float64(nspaces)
+		for i, r := range line.runes {
+			line.xs[i] += dx
+			if unicode.IsSpace(r) {
+				dx += extra
+			}
+		}
+		line.width = width
+		return
+	}
+	for i := range line.xs {
+		line.xs[i] += dx
+	}
+}
+
+// glyphAdvance returns the horizontal advance in pixels of the given character
+// at the specified font size, excluding letter spacing.
+func glyphAdvance(_font C.Font, r rune, fontSize float64) float64 {
+	if _font.glyphCount == 0 {
+		return 0
+	}
+	index := C.GetGlyphIndex(_font, C.int(r))
+	glyph := unsafe.Slice(_font.glyphs, _font.glyphCount)[index]
+	scale := fontSize / float64(_font.baseSize)
+	// ref: DrawTextEx (raylib/src/rtext.c)
+	if glyph.advanceX == 0 {
+		rec := unsafe.Slice(_font.recs, _font.glyphCount)[index]
+		return float64(rec.width) * scale
+	}
+	return float64(glyph.advanceX) * scale
+}
+
+// drawText draws the laid out text entry at the given position, scaled and
+// rotated in degrees around the origin, relative to the position.
+func drawText(text *Text, pos, origin C.Vector2, rotation, scale float64, c color.Color) {
+	lay := text.layout()
+	_font := text.raylibFont()
+	_c := raylibColor(c)
+	fontSize := C.float(text.fontSize)
+	C.rlPushMatrix()
+	C.rlTranslatef(pos.x, pos.y, 0)
+	C.rlRotatef(C.float(rotation), 0, 0, 1)
+	C.rlTranslatef(-origin.x, -origin.y, 0)
+	C.rlScalef(C.float(scale), C.float(scale), 1)
+	for _, line := range lay.lines {
+		for i, r := range line.runes {
+			if r == ' ' || r == '\t' {
+				continue
+			}
+			_pos := C.Vector2{x: C.float(line.xs[i]), y: C.float(line.y)}
+			C.DrawTextCodepoint(_font, C.int(r), _pos, fontSize, _c)
+		}
+	}
+	C.rlPopMatrix()
+}
+
+// trimSpaceLeft returns the given characters with leading spaces removed.
+func trimSpaceLeft(runes []rune) []rune {
+	for len(runes) > 0 && unicode.IsSpace(runes[0]) {
+		runes = runes[1:]
+	}
+	return runes
+}
+
+// trimSpaceRight returns the given characters with trailing spaces removed.
+func trimSpaceRight(runes []rune) []rune {
+	for len(runes) > 0 && unicode.IsSpace(runes[len(runes)-1]) {
+		runes = runes[:len(runes)-1]
+	}
+	return runes
+}
@@ -0,0 +1,123 @@
+package window
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTextLayout(t *testing.T) {
+	golden := []struct {
+		s        string
+		maxWidth int
+		maxLines int
+		want     []string
+		height   float64
+	}{
+		// Explicit newlines.
+		{s: "hello world", want: []string{"hello world"}, height: 10},
+		{s: "a\nb", want: []string{"a", "b"}, height: 25},
+		{s: "a\n\nb", want: []string{"a", "", "b"}, height: 40},
+		// Wrap at spaces.
+		{s: "aaa bbb ccc", maxWidth: 70, want: []string{"aaa bbb", "ccc"}, height: 25},
+		{s: "aaa   bbb", maxWidth: 50, want: []string{"aaa", "bbb"}, height: 25},
+		// Trailing spaces are not wrapped onto lines of their own.
+		{s: "aaa   ", maxWidth: 30, want: []string{"aaa"}, height: 10},
+		{s: "aaa bbb   \nccc", maxWidth: 30, want: []string{"aaa", "bbb", "ccc"}, height: 40},
+		// Break words wider than the maximum width.
+		{s: "aaaaa", maxWidth: 20, want: []string{"aa", "aa", "a"}, height: 40},
+		{s: "a", maxWidth: 5, want: []string{"a"}, height: 10},
+		{s: "ab", maxWidth: 5, want: []string{"a", "b"}, height: 25},
+		{s: "  aaaaaa b", maxWidth: 50, want: []string{"  aaa", "aaa b"}, height: 25},
+		// Truncate to the maximum number of lines.
+		{s: "a\nb\nc", maxLines: 2, want: []string{"a", "b..."}, height: 25},
+		{s: "aaa bbb ccc", maxWidth: 70, maxLines: 1, want: []string{"aaa..."}, height: 10},
+		{s: "aaa bbb ccc", maxWidth: 30, maxLines: 1, want: []string{"..."}, height: 10},
+		{s: "aaa bbb", maxWidth: 30, maxLines: 2, want: []string{"aaa", "bbb"}, height: 25},
+	}
+	for _, g := range golden {
+		text := newTestText(g.s)
+		text.maxWidth = g.maxWidth
+		text.maxLines = g.maxLines
+		lay := text.layoutWith(testAdvance)
+		var got []string
+		for _, line := range lay.lines {
+			got = append(got, string(line.runes))
+		}
+		if !slices.Equal(got, g.want) {
+			t.Errorf("%q (max width %d, max lines %d): lines mismatch; expected %q, got %q", g.s, g.maxWidth, g.maxLines, g.want, got)
+		}
+		if lay.height != g.height {
+			t.Errorf("%q (max width %d, max lines %d): height mismatch; expected %v, got %v", g.s, g.maxWidth, g.maxLines, g.height, lay.height)
+		}
+	}
+}
+
+func TestTextAlign(t *testing.T) {
+	golden := []struct {
+		s        string
+		maxWidth int
+		align    Align
+		// Horizontal position of the first character of each line.
+		want []float64
+		// Width of the text entry.
+		width float64
+	}{
+		{s: "aaa\nb", align: AlignLeft, want: []float64{0, 0}, width: 30},
+		{s: "aaa\nb", align: AlignCenter, want: []float64{0, 10}, width: 30},
+		{s: "aaa\nb", align: AlignRight, want: []float64{0, 20}, width: 30},
+		// Align within the maximum width.
+		{s: "aaa", maxWidth: 100, align: AlignCenter, want: []float64{35}, width: 100},
+		{s: "aaa", maxWidth: 100, align: AlignRight, want: []float64{70}, width: 100},
+	}
+	for _, g := range golden {
+		text := newTestText(g.s)
+		text.maxWidth = g.maxWidth
+		text.align = g.align
+		lay := text.layoutWith(testAdvance)
+		var got []float64
+		for _, line := range lay.lines {
+			got = append(got, line.xs[0])
+		}
+		if !slices.Equal(got, g.want) {
+			t.Errorf("%q (%v): line positions mismatch; expected %v, got %v", g.s, g.align, g.want, got)
+		}
+		if lay.width != g.width {
+			t.Errorf("%q (%v): width mismatch; expected %v, got %v", g.s, g.align, g.width, lay.width)
+		}
+	}
+}
+
+func TestTextJustify(t *testing.T) {
+	text := newTestText("aa bb cc")
+	text.maxWidth = 60
+	text.align = AlignJustify
+	lay := text.layoutWith(testAdvance)
+	if len(lay.lines) != 2 {
+		t.Fatalf("number of lines mismatch; expected 2, got %d", len(lay.lines))
+	}
+	// The space of the wrapped line is stretched to fill the maximum width.
+	want := []float64{0, 10, 20, 40, 50}
+	if got := lay.lines[0].xs; !slices.Equal(got, want) {
+		t.Errorf("wrapped line positions mismatch; expected %v, got %v", want, got)
+	}
+	// The last line of the paragraph is aligned to the left.
+	want = []float64{0, 10}
+	if got := lay.lines[1].xs; !slices.Equal(got, want) {
+		t.Errorf("last line positions mismatch; expected %v, got %v", want, got)
+	}
+}
+
+// newTestText returns a new text entry of the given text string, laid out with
+// a font size of 10 pixels and no letter spacing.
+func newTestText(s string) *Text {
+	return &Text{
+		str:        s,
+		fontSize:   10,
+		lineHeight: defaultLineHeight,
+	}
+}
+
+// testAdvance returns a fixed advance of 10 pixels for each character.
+func testAdvance(r rune) float64 {
+	return 10
+}
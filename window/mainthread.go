@@ -0,0 +1,140 @@
+package window
+
+// static _Thread_local int mainThread;
+//
+// // markMainThread marks the current OS thread as the main thread.
+// static void markMainThread(void) {
+// 	mainThread = 1;
+// }
+//
+// // isMainThread reports whether the current OS thread is the main thread.
+// static int isMainThread(void) {
+// 	return mainThread;
+// }
+import "C"
+
+import (
+	"fmt"
+)
+
+// raylib (and OpenGL) must be called from the main thread, which owns the
+// window and OpenGL context. The main goroutine is locked to the main thread
+// in init; other goroutines use Do and DoAsync to call functions on the main
+// thread.
+
+// callQueueSize specifies the capacity of the call queue; DoAsync blocks while
+// the call queue is full, unless called from the main thread.
+const callQueueSize = 64
+
+// callQueue holds functions to be called on the main thread, by Run or
+// Window.Display.
+var callQueue = make(chan func(), callQueueSize)
+
+// Run calls f on a new goroutine, while calling functions passed to Do and
+// DoAsync on the main thread until f returns. Run must be called from the main
+// goroutine; typically from the main function of the program.
+//
+// Example:
+//
+//	func main() {
+//		window.Run(run)
+//	}
+//
+//	func run() {
+//		// Receive messages on another goroutine.
+//		msgs := make(chan string)
+//		go receive(msgs)
+//		for msg := range msgs {
+//			window.Do(func() {
+//				text.SetText(msg)
+//			})
+//		}
+//	}
+func Run(f func()) {
+	if !onMainThread() {
+		panic("window.Run must be called from the main goroutine")
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	for {
+		select {
+		case call := <-callQueue:
+			call()
+		case <-done:
+			// Call remaining functions queued by DoAsync.
+			handleCalls()
+			return
+		}
+	}
+}
+
+// Do calls f on the main thread, and waits for it to return. Do calls f
+// directly if called from the main thread. A panic in f is propagated to the
+// caller of Do.
+//
+// Note: functions are called on the main thread by Run, or upon the next call
+// to Window.Display.
+func Do(f func()) {
+	if onMainThread() {
+		f()
+		return
+	}
+	done := make(chan any)
+	callQueue <- func() {
+		defer func() {
+			done <- recover()
+		}()
+		f()
+	}
+	if err := <-done; err != nil {
+		panic(err)
+	}
+}
+
+// DoAsync queues f to be called on the main thread, without waiting for it to
+// return. DoAsync calls f directly if called from the main thread, as the main
+// thread would otherwise deadlock while the call queue is full.
+//
+// Note: functions are called on the main thread by Run, or upon the next call
+// to Window.Display.
+func DoAsync(f func()) {
+	if onMainThread() {
+		f()
+		return
+	}
+	callQueue <- f
+}
+
+// handleCalls calls the functions queued by Do and DoAsync.
+func handleCalls() {
+	for {
+		select {
+		case call := <-callQueue:
+			call()
+		default:
+			return
+		}
+	}
+}
+
+// markMainThread marks the OS thread of the calling goroutine as the main
+// thread.
+func markMainThread() {
+	C.markMainThread()
+}
+
+// onMainThread reports whether the calling goroutine runs on the main thread.
+func onMainThread() bool {
+	return C.isMainThread() != 0
+}
+
+// checkMainThread panics if the calling goroutine doesn't run on the main
+// thread, when built with the raylibdebug build tag.
+func checkMainThread() {
+	if debug && !onMainThread() {
+		panic(fmt.Errorf("window: raylib called from goroutine not running on the main thread; use window.Do to call from other goroutines"))
+	}
+}
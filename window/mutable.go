@@ -0,0 +1,151 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+import (
+	"image"
+	"unsafe"
+
+	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
+)
+
+// MutableTexture represents a texture which may be updated from Go images;
+// e.g. to stream procedurally generated pixels each frame. It implements the
+// wandi.Image interface.
+//
+// Pixels are stored with straight (non-premultiplied) alpha, as with
+// LoadTextureFromImage; *image.NRGBA images are uploaded without conversion,
+// and *image.RGBA images are converted from premultiplied alpha.
+type MutableTexture struct {
+	*Texture
+	// Contiguous pixel data of straight alpha, reused across updates from
+	// images which must be converted or copied before upload.
+	scratch []byte
+}
+
+// NewMutableTexture returns a new mutable texture of the specified dimensions,
+// with transparent pixels.
+//
+// The returned error wraps ErrGPUUpload if the texture could not be created on
+// the GPU.
+//
+// Note: a finalizer is registered to unload the texture.
+func NewMutableTexture(width, height int) (*MutableTexture, error) {
+	checkMainThread()
+	if width <= 0 || height <= 0 {
+		return nil, errors.Errorf("invalid mutable texture dimensions %dx%d", width, height)
+	}
+	// Upload transparent pixels, as the contents of textures created without
+	// pixel data are undefined.
+	pix := make([]byte, width*height*npixelBytes)
+	const format = C.PIXELFORMAT_UNCOMPRESSED_R8G8B8A8
+	id := C.rlLoadTexture(unsafe.Pointer(&pix[0]), C.int(width), C.int(height), format, 1)
+	if id == 0 {
+		return nil, errors.Wrapf(ErrGPUUpload, "unable to create mutable texture of dimensions %dx%d", width, height)
+	}
+	_tex := C.Texture2D{
+		id:      id,
+		width:   C.int(width),
+		height:  C.int(height),
+		mipmaps: 1,
+		format:  format,
+	}
+	mtex := &MutableTexture{
+		Texture: newTexture(_tex),
+	}
+	return mtex, nil
+}
+
+// Update replaces the pixels of the texture with the pixels of the src image,
+// which must be of the same dimensions as the texture.
+//
+// Note: *image.NRGBA images of contiguous pixel data are uploaded without
+// copying.
+func (mtex *MutableTexture) Update(src image.Image) error {
+	checkMainThread()
+	if mtex._tex.id == 0 {
+		return errors.New("unable to update freed texture")
+	}
+	bounds := src.Bounds()
+	if bounds.Dx() != mtex.Width() || bounds.Dy() != mtex.Height() {
+		return errors.Errorf("invalid image dimensions %dx%d; expected %dx%d", bounds.Dx(), bounds.Dy(), mtex.Width(), mtex.Height())
+	}
+	if bounds.Empty() {
+		return nil
+	}
+	pix := mtex.pix(src)
+	// Draw pending draw operations using the previous contents of the texture
+	// before updating it.
+	flushBatch()
+	C.UpdateTexture(mtex._tex, unsafe.Pointer(&pix[0]))
+	return nil
+}
+
+// UpdateRect replaces the pixels of the texture within the rectangle r with the
+// pixels of the src image, which must be of the same dimensions as r.
+//
+// Note: *image.NRGBA images of contiguous pixel data are uploaded without
+// copying.
+func (mtex *MutableTexture) UpdateRect(r image.Rectangle, src image.Image) error {
+	checkMainThread()
+	if mtex._tex.id == 0 {
+		return errors.New("unable to update freed texture")
+	}
+	if !r.In(image.Rect(0, 0, mtex.Width(), mtex.Height())) {
+		return errors.Errorf("invalid update rectangle %v; outside of %dx%d texture", r, mtex.Width(), mtex.Height())
+	}
+	bounds := src.Bounds()
+	if bounds.Size() != r.Size() {
+		return errors.Errorf("invalid image dimensions %dx%d; expected %dx%d", bounds.Dx(), bounds.Dy(), r.Dx(), r.Dy())
+	}
+	if r.Empty() {
+		return nil
+	}
+	pix := mtex.pix(src)
+	// Draw pending draw operations using the previous contents of the texture
+	// before updating it.
+	flushBatch()
+	_rect := raylibRectangle(r)
+	C.UpdateTextureRec(mtex._tex, _rect, unsafe.Pointer(&pix[0]))
+	return nil
+}
+
+// Ensure that MutableTexture implements wandi.Image.
+var _ wandi.Image = (*MutableTexture)(nil)
+
+// ### [ Helper functions ] ####################################################
+
+// pix returns the pixels of the src image as contiguous 8-bit RGBA pixel data
+// of straight alpha. Contiguous *image.NRGBA images are returned without
+// copying, and other *image.NRGBA and *image.RGBA images are converted into
+// the scratch buffer of the texture, which is reused across updates.
+func (mtex *MutableTexture) pix(src image.Image) []byte {
+	bounds := src.Bounds()
+	n := npixelBytes * bounds.Dx() * bounds.Dy()
+	switch src := src.(type) {
+	case *image.NRGBA:
+		if src.Stride == npixelBytes*bounds.Dx() {
+			return src.Pix[:n]
+		}
+		buf := mtex.scratchBuf(n)
+		copyRows(buf, src.Pix, src.Stride, bounds)
+		return buf
+	case *image.RGBA:
+		buf := mtex.scratchBuf(n)
+		unpremultiply(buf, src)
+		return buf
+	default:
+		return fallbackNRGBAImage(src).Pix
+	}
+}
+
+// scratchBuf returns the scratch buffer of the texture, of length n.
+func (mtex *MutableTexture) scratchBuf(n int) []byte {
+	if cap(mtex.scratch) < n {
+		mtex.scratch = make([]byte, n)
+	}
+	return mtex.scratch[:n]
+}
@@ -0,0 +1,47 @@
+package window
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestMutableTexturePix(t *testing.T) {
+	var (
+		rgba  = image.NewRGBA(image.Rect(0, 0, 3, 3))
+		nrgba = image.NewNRGBA(image.Rect(0, 0, 3, 3))
+	)
+	for i := range 9 {
+		x, y := i%3, i/3
+		c := color.NRGBA{R: uint8(i * 31), G: 0x80, B: 0xFF, A: uint8(i * 255 / 8)}
+		rgba.Set(x, y, c)
+		nrgba.Set(x, y, c)
+	}
+	mtex := &MutableTexture{Texture: &Texture{}}
+	golden := []image.Image{
+		rgba,
+		rgba.SubImage(image.Rect(1, 1, 3, 3)),
+		nrgba,
+		nrgba.SubImage(image.Rect(1, 1, 3, 3)),
+	}
+	for _, src := range golden {
+		want := contiguousNRGBAImage(src).Pix
+		if got := mtex.pix(src); !bytes.Equal(got, want) {
+			t.Errorf("%T %v: pixels mismatch; expected %v, got %v", src, src.Bounds(), want, got)
+		}
+	}
+	// Contiguous NRGBA images are uploaded without copying.
+	if got := mtex.pix(nrgba); &got[0] != &nrgba.Pix[0] {
+		t.Errorf("contiguous NRGBA pixels copied")
+	}
+	// The scratch buffer is reused across updates.
+	sub := nrgba.SubImage(image.Rect(0, 0, 2, 2))
+	allocs := testing.AllocsPerRun(10, func() {
+		mtex.pix(rgba)
+		mtex.pix(sub)
+	})
+	if allocs != 0 {
+		t.Errorf("allocations mismatch; expected 0, got %v", allocs)
+	}
+}
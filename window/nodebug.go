@@ -0,0 +1,26 @@
+//go:build !raylibdebug
+
+package window
+
+import (
+	"unsafe"
+)
+
+// debug specifies whether the package is built in debug mode, which panics
+// when raylib is called from goroutines not running on the main thread, and
+// tracks C allocations of the package.
+const debug = false
+
+// trackAlloc records the given C allocation; a no-op when not built in debug
+// mode.
+func trackAlloc(p unsafe.Pointer, skip int) {}
+
+// untrackAlloc records that the given C allocation has been freed; a no-op
+// when not built in debug mode.
+func untrackAlloc(p unsafe.Pointer) {}
+
+// outstandingAllocs returns the number of outstanding C allocations, indexed
+// by allocation site; always empty when not built in debug mode.
+func outstandingAllocs() map[string]int {
+	return nil
+}
@@ -0,0 +1,132 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+// An Option customizes the configuration of a window created by Open.
+type Option func(opts *options)
+
+// options holds the configuration of a window created by Open.
+type options struct {
+	// Window title.
+	title string
+	// raylib configuration flags.
+	flags C.uint
+	// Target frames per second; or 0 if unlimited.
+	fps int
+	// Trace log level of raylib.
+	logLevel LogLevel
+}
+
+// defaultOptions returns the default window configuration.
+func defaultOptions() *options {
+	return &options{
+		title:    "raylib",
+		logLevel: LogWarning,
+	}
+}
+
+// WithTitle sets the title of the window. The default title is "raylib".
+func WithTitle(title string) Option {
+	return func(opts *options) {
+		opts.title = title
+	}
+}
+
+// WithResizable allows the window to be resized by the user.
+func WithResizable() Option {
+	return withFlags(C.FLAG_WINDOW_RESIZABLE)
+}
+
+// WithUndecorated disables window decorations (frame and buttons).
+func WithUndecorated() Option {
+	return withFlags(C.FLAG_WINDOW_UNDECORATED)
+}
+
+// WithTransparent enables a transparent framebuffer for the window.
+func WithTransparent() Option {
+	return withFlags(C.FLAG_WINDOW_TRANSPARENT)
+}
+
+// WithMSAA4x enables 4x multisample anti-aliasing, if supported.
+func WithMSAA4x() Option {
+	return withFlags(C.FLAG_MSAA_4X_HINT)
+}
+
+// WithVSync enables vertical synchronization with the monitor refresh rate, if
+// supported.
+func WithVSync() Option {
+	return withFlags(C.FLAG_VSYNC_HINT)
+}
+
+// WithHighDPI enables support for high-DPI monitors.
+func WithHighDPI() Option {
+	return withFlags(C.FLAG_WINDOW_HIGHDPI)
+}
+
+// WithAlwaysOnTop keeps the window on top of other windows.
+func WithAlwaysOnTop() Option {
+	return withFlags(C.FLAG_WINDOW_TOPMOST)
+}
+
+// WithHidden hides the window on start.
+func WithHidden() Option {
+	return withFlags(C.FLAG_WINDOW_HIDDEN)
+}
+
+// WithFullscreen opens the window in fullscreen mode.
+func WithFullscreen() Option {
+	return withFlags(C.FLAG_FULLSCREEN_MODE)
+}
+
+// WithTargetFPS sets the target frames per second of the window. Window.Display
+// waits as needed to not exceed the target frame rate. The default is 0, which
+// does not limit the frame rate.
+func WithTargetFPS(fps int) Option {
+	return func(opts *options) {
+		opts.fps = fps
+	}
+}
+
+// WithLogLevel sets the trace log level of raylib. The default log level is
+// LogWarning.
+func WithLogLevel(level LogLevel) Option {
+	return func(opts *options) {
+		opts.logLevel = level
+	}
+}
+
+// withFlags returns an option which sets the given raylib configuration flags.
+func withFlags(flags C.uint) Option {
+	return func(opts *options) {
+		opts.flags |= flags
+	}
+}
+
+// logLevel specifies the current trace log level of raylib. Initialized by
+// Open.
+var logLevel = LogInfo
+
+// LogLevel specifies the trace log level of raylib. Messages with a lower log
+// level than the current log level are discarded.
+type LogLevel int
+
+// Trace log levels.
+const (
+	// Display all logs.
+	LogAll LogLevel = C.LOG_ALL
+	// Trace logging, intended for internal use only.
+	LogTrace LogLevel = C.LOG_TRACE
+	// Debug logging, used for internal debugging.
+	LogDebug LogLevel = C.LOG_DEBUG
+	// Info logging, used for program execution info.
+	LogInfo LogLevel = C.LOG_INFO
+	// Warning logging, used on recoverable failures.
+	LogWarning LogLevel = C.LOG_WARNING
+	// Error logging, used on unrecoverable failures.
+	LogError LogLevel = C.LOG_ERROR
+	// Fatal logging, used to abort program.
+	LogFatal LogLevel = C.LOG_FATAL
+	// Disable logging.
+	LogNone LogLevel = C.LOG_NONE
+)
@@ -0,0 +1,51 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+import (
+	"sync"
+)
+
+// Finalizers run on the goroutine of the garbage collector, rather than on the
+// main thread, which owns the OpenGL context. Therefore, finalizers of GPU
+// resources only queue the release of the resources, which are released on
+// the main thread upon the next call to Window.Display.
+
+var (
+	// releaseQueue holds the functions releasing GPU resources of finalized
+	// objects, called by Window.Display.
+	releaseQueue []func()
+	// releaseMutex protects releaseQueue.
+	releaseMutex sync.Mutex
+)
+
+// deferRelease queues the given release function, to be called on the main
+// thread upon the next call to Window.Display.
+//
+// Note: deferRelease is safe for concurrent use, and intended to be called
+// from finalizers.
+func deferRelease(release func()) {
+	releaseMutex.Lock()
+	defer releaseMutex.Unlock()
+	releaseQueue = append(releaseQueue, release)
+}
+
+// releaseResources releases the GPU resources of objects finalized since the
+// last call to Window.Display.
+func releaseResources() {
+	releaseMutex.Lock()
+	queue := releaseQueue
+	releaseQueue = nil
+	releaseMutex.Unlock()
+	for _, release := range queue {
+		release()
+	}
+}
+
+// flushBatch draws pending draw operations of the active draw target, before
+// releasing GPU resources which may be used by pending draw operations.
+func flushBatch() {
+	C.rlDrawRenderBatchActive()
+}
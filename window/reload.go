@@ -0,0 +1,144 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A ShaderReload event is triggered when a watched shader has been reloaded
+// after a change to its source files.
+type ShaderReload struct {
+	// Reloaded shader.
+	Shader *Shader
+	// Error encountered while reloading the shader; or nil on success. On
+	// error, the shader keeps using the previous working shader program, and
+	// compile errors are reported as a *CompileError.
+	Err error
+}
+
+// shaderPollInterval specifies the minimum interval between checking watched
+// shader source files for changes.
+const shaderPollInterval = 250 * time.Millisecond
+
+// shaderWatch tracks the source files of a watched shader.
+type shaderWatch struct {
+	// Paths of the vertex and fragment shader source files.
+	vsPath, fsPath string
+	// Modification time of the vertex and fragment shader source files.
+	vsModTime, fsModTime time.Time
+	// Uniform setters, indexed by uniform name; re-applied when the shader is
+	// reloaded.
+	uniforms map[string]func(shader *Shader)
+}
+
+var (
+	// watchedShaders holds the shaders reloaded on change.
+	watchedShaders []*Shader
+	// lastShaderPoll specifies the time watched shader source files were last
+	// checked for changes.
+	lastShaderPoll time.Time
+)
+
+// LoadShaderWatched loads the given vertex and fragment shader, and reloads the
+// shader upon call to Window.Display when its source files change. The default
+// vertex or fragment shader is used if vsPath or fsPath is empty, respectively.
+// ShaderReload events report the outcome of reloading the shader, and uniform
+// values set on the shader are re-applied after each successful reload.
+//
+// The returned error wraps ErrNotFound if a shader file does not exist, and
+// ErrCompile (as a *CompileError) if the shader program could not be compiled
+// or linked.
+//
+// Note: watched shaders are not unloaded until Unwatch is invoked.
+func LoadShaderWatched(vsPath, fsPath string) (*Shader, error) {
+	checkMainThread()
+	shader, err := LoadShader(vsPath, fsPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	shader.watch = &shaderWatch{
+		vsPath:    vsPath,
+		fsPath:    fsPath,
+		vsModTime: modTime(vsPath),
+		fsModTime: modTime(fsPath),
+		uniforms:  make(map[string]func(shader *Shader)),
+	}
+	watchedShaders = append(watchedShaders, shader)
+	return shader, nil
+}
+
+// Unwatch stops reloading the shader when its source files change.
+func (shader *Shader) Unwatch() {
+	checkMainThread()
+	for i, s := range watchedShaders {
+		if s == shader {
+			watchedShaders = append(watchedShaders[:i], watchedShaders[i+1:]...)
+			break
+		}
+	}
+	shader.watch = nil
+}
+
+// reloadShaders reloads the watched shaders with changed source files, and
+// fills the event queue with the outcome.
+func reloadShaders() {
+	if len(watchedShaders) == 0 || time.Since(lastShaderPoll) < shaderPollInterval {
+		return
+	}
+	lastShaderPoll = time.Now()
+	for _, shader := range watchedShaders {
+		watch := shader.watch
+		vsModTime, fsModTime := modTime(watch.vsPath), modTime(watch.fsPath)
+		if vsModTime.Equal(watch.vsModTime) && fsModTime.Equal(watch.fsModTime) {
+			continue
+		}
+		watch.vsModTime, watch.fsModTime = vsModTime, fsModTime
+		event := ShaderReload{
+			Shader: shader,
+			Err:    shader.reload(),
+		}
+		eventQueue.PushBack(event)
+	}
+}
+
+// reload reloads the shader from its source files. The previous shader program
+// is kept on error.
+func (shader *Shader) reload() error {
+	watch := shader.watch
+	_shader, err := loadShader(watch.vsPath, watch.fsPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// Draw pending draw operations using the previous shader program before
+	// unloading it.
+	flushBatch()
+	C.UnloadShader(shader._shader)
+	shader._shader = _shader
+	// Invalidate cached locations of the previous shader program.
+	clear(shader.locs)
+	clear(shader.attribLocs)
+	clear(shader.textures)
+	// Re-apply uniform values.
+	for _, set := range watch.uniforms {
+		set(shader)
+	}
+	return nil
+}
+
+// modTime returns the modification time of the given file; or the zero time if
+// the path is empty or the file could not be accessed.
+func modTime(path string) time.Time {
+	if len(path) == 0 {
+		return time.Time{}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
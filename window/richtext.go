@@ -0,0 +1,426 @@
+package window
+
+// #include <raylib.h>
+// #include <rlgl.h>
+import "C"
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
+)
+
+// A Span is a run of text with uniform style within a rich text entry.
+type Span struct {
+	// Text of the span.
+	Text string
+	// Font of the span; or the default font if nil.
+	Font *Font
+	// Font size in pixels; or 12 if zero.
+	Size int
+	// Text colour; or black if nil.
+	Color color.Color
+	// Draw a line below the text.
+	Underline bool
+	// Draw a line through the text.
+	Strikethrough bool
+}
+
+// RichText represents a graphical text entry of styled spans, each with a
+// specific font, font size, colour and decoration. It implements the
+// wandi.Image interface.
+//
+// Spans are laid out after each other, and lines are broken at explicit
+// newlines. The spans of each line are aligned at the bottom.
+type RichText struct {
+	// Styled spans of the text.
+	spans []Span
+	// Layout of the text; or nil if not yet laid out.
+	lay *richLayout
+}
+
+// NewRichText returns a new graphical rich text entry of the given spans.
+func NewRichText(spans ...Span) *RichText {
+	text := &RichText{}
+	text.SetSpans(spans...)
+	return text
+}
+
+// SetSpans sets the styled spans of the rich text entry.
+func (text *RichText) SetSpans(spans ...Span) {
+	text.spans = spans
+	text.lay = nil
+}
+
+// Spans returns the styled spans of the rich text entry.
+func (text *RichText) Spans() []Span {
+	return text.spans
+}
+
+// Width returns the width of the rich text entry.
+func (text *RichText) Width() int {
+	checkMainThread()
+	return int(math.Ceil(text.layout().width))
+}
+
+// Height returns the height of the rich text entry.
+func (text *RichText) Height() int {
+	checkMainThread()
+	return int(math.Ceil(text.layout().height))
+}
+
+// Ensure that RichText implements wandi.Image.
+var _ wandi.Image = (*RichText)(nil)
+
+// --- [ markup ] --------------------------------------------------------------
+
+// ParseMarkup parses the given markup into styled spans. The following tags
+// are supported, each terminated by a closing tag (e.g. [/color]):
+//
+//	[color=#f00]   text colour, as #rgb, #rgba, #rrggbb or #rrggbbaa
+//	[size=20]      font size in pixels
+//	[font=name]    font, as specified by name in the fonts map
+//	[u]            underline
+//	[s]            strikethrough
+//
+// Tags may be nested. A literal '[' is written as "[[".
+//
+// Example:
+//
+//	spans, err := window.ParseMarkup("[color=#f00]Error:[/color] [u]file not found[/u]", nil)
+func ParseMarkup(markup string, fonts map[string]*Font) ([]Span, error) {
+	var (
+		spans         []Span
+		colors        []color.Color
+		sizes         []int
+		spanFonts     []*Font
+		underline     int
+		strikethrough int
+		buf           strings.Builder
+	)
+	// flush appends the text of buf as a span of the current style.
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		span := Span{
+			Text:          buf.String(),
+			Underline:     underline > 0,
+			Strikethrough: strikethrough > 0,
+		}
+		if len(colors) > 0 {
+			span.Color = colors[len(colors)-1]
+		}
+		if len(sizes) > 0 {
+			span.Size = sizes[len(sizes)-1]
+		}
+		if len(spanFonts) > 0 {
+			span.Font = spanFonts[len(spanFonts)-1]
+		}
+		spans = append(spans, span)
+		buf.Reset()
+	}
+	for len(markup) > 0 {
+		pos := strings.IndexByte(markup, '[')
+		if pos == -1 {
+			buf.WriteString(markup)
+			break
+		}
+		buf.WriteString(markup[:pos])
+		markup = markup[pos:]
+		if strings.HasPrefix(markup, "[[") {
+			buf.WriteByte('[')
+			markup = markup[len("[["):]
+			continue
+		}
+		end := strings.IndexByte(markup, ']')
+		if end == -1 {
+			return nil, errors.Errorf("unterminated tag in markup %q", markup)
+		}
+		tag := markup[1:end]
+		markup = markup[end+1:]
+		flush()
+		name, arg, hasArg := strings.Cut(tag, "=")
+		takesArg := name == "color" || name == "size" || name == "font"
+		if hasArg != takesArg {
+			return nil, errors.Errorf("invalid tag [%s] in markup", tag)
+		}
+		switch name {
+		case "color":
+			c, err := parseHexColor(arg)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			colors = append(colors, c)
+		case "size":
+			size, err := strconv.Atoi(arg)
+			if err != nil || size <= 0 {
+				return nil, errors.Errorf("invalid font size %q in markup", arg)
+			}
+			sizes = append(sizes, size)
+		case "font":
+			font, ok := fonts[arg]
+			if !ok {
+				return nil, errors.Errorf("unknown font %q in markup", arg)
+			}
+			spanFonts = append(spanFonts, font)
+		case "u":
+			underline++
+		case "s":
+			strikethrough++
+		case "/color", "/size", "/font", "/u", "/s":
+			ok := true
+			switch name {
+			case "/color":
+				colors, ok = popStyle(colors)
+			case "/size":
+				sizes, ok = popStyle(sizes)
+			case "/font":
+				spanFonts, ok = popStyle(spanFonts)
+			case "/u":
+				ok = underline > 0
+				underline--
+			case "/s":
+				ok = strikethrough > 0
+				strikethrough--
+			}
+			if !ok {
+				return nil, errors.Errorf("unmatched closing tag [%s] in markup", tag)
+			}
+		default:
+			return nil, errors.Errorf("unknown tag [%s] in markup", tag)
+		}
+	}
+	flush()
+	return spans, nil
+}
+
+// popStyle pops the top style of the given style stack, and reports whether
+// the stack was non-empty.
+func popStyle[T any](stack []T) ([]T, bool) {
+	if len(stack) == 0 {
+		return stack, false
+	}
+	return stack[:len(stack)-1], true
+}
+
+// parseHexColor parses the given hexadecimal colour, as #rgb, #rgba, #rrggbb
+// or #rrggbbaa.
+func parseHexColor(s string) (color.Color, error) {
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok {
+		return nil, errors.Errorf("invalid colour %q; expected hexadecimal colour prefixed with '#'", s)
+	}
+	if len(hex) == 3 || len(hex) == 4 {
+		// Expand short form (e.g. "f00" to "ff0000").
+		var long []byte
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, errors.Errorf("invalid colour %q; expected #rgb, #rgba, #rrggbb or #rrggbbaa", s)
+	}
+	x, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, errors.Errorf("invalid colour %q; expected hexadecimal digits", s)
+	}
+	c := color.NRGBA{
+		R: uint8(x >> 24),
+		G: uint8(x >> 16),
+		B: uint8(x >> 8),
+		A: uint8(x),
+	}
+	return c, nil
+}
+
+// --- [ layout ] --------------------------------------------------------------
+
+// richLayout is the layout of a rich text entry.
+type richLayout struct {
+	// Laid out runs.
+	runs []*richRun
+	// Dimensions of the bounding box of the rich text entry.
+	width, height float64
+}
+
+// richRun is a laid out run of a span within a single line.
+type richRun struct {
+	// Span of the run.
+	span *Span
+	// Characters of the run.
+	runes []rune
+	// Horizontal position of each character, relative to the left edge of the
+	// rich text entry.
+	xs []float64
+	// Vertical position of the top of the run, relative to the top edge of the
+	// rich text entry.
+	y float64
+	// Horizontal position of the right edge of the run.
+	end float64
+	// Font size in pixels.
+	size float64
+	// Glyphs of the run have been rasterized into the atlas of the dynamic font
+	// of the span, as of the given atlas generation.
+	rasterized bool
+	dynGen     int
+}
+
+// layout returns the layout of the rich text entry, laying out the text if not
+// yet laid out.
+func (text *RichText) layout() *richLayout {
+	if text.lay != nil {
+		return text.lay
+	}
+	lay := &richLayout{}
+	var (
+		// Runs of the current line.
+		line []*richRun
+		// Pen position.
+		x, y float64
+		// Height of the current line; i.e. the largest font size of the line.
+		lineSize float64
+	)
+	endLine := func() {
+		// Align runs at the bottom of the line.
+		for _, run := range line {
+			run.y = y + lineSize - run.size
+		}
+		lay.height = y + lineSize
+		y += lineSize * defaultLineHeight
+		line, x, lineSize = nil, 0, 0
+	}
+	for i := range text.spans {
+		span := &text.spans[i]
+		size := float64(span.size())
+		_font := raylibFontOf(span.Font, span.Text)
+		for j, part := range strings.Split(span.Text, "\n") {
+			if j > 0 {
+				endLine()
+			}
+			lineSize = math.Max(lineSize, size)
+			if len(part) == 0 {
+				continue
+			}
+			run := &richRun{
+				span:  span,
+				runes: []rune(part),
+				size:  size,
+			}
+			run.xs = make([]float64, len(run.runes))
+			for k, r := range run.runes {
+				run.xs[k] = x
+				run.end = x + glyphAdvance(_font, r, size)
+				x = run.end + defaultSpacing
+			}
+			lay.width = math.Max(lay.width, run.end)
+			line = append(line, run)
+			lay.runs = append(lay.runs, run)
+		}
+	}
+	endLine()
+	text.lay = lay
+	return lay
+}
+
+// drawRichText draws the laid out rich text entry at the given position, scaled
+// and rotated in degrees around the origin, relative to the position. The
+// colour of each span is multiplied with the tint colour.
+func drawRichText(text *RichText, pos, origin C.Vector2, rotation, scale float64, tint color.Color) {
+	lay := text.layout()
+	C.rlPushMatrix()
+	C.rlTranslatef(pos.x, pos.y, 0)
+	C.rlRotatef(C.float(rotation), 0, 0, 1)
+	C.rlTranslatef(-origin.x, -origin.y, 0)
+	C.rlScalef(C.float(scale), C.float(scale), 1)
+	for _, run := range lay.runs {
+		// Note: the raylib font is retrieved for each run, as rasterizing glyphs
+		// of dynamic fonts may update the underlying raylib font.
+		_font := run.raylibFont()
+		_c := raylibColor(mulColor(run.span.color(), tint))
+		for i, r := range run.runes {
+			if r == ' ' || r == '\t' {
+				continue
+			}
+			_pos := C.Vector2{x: C.float(run.xs[i]), y: C.float(run.y)}
+			C.DrawTextCodepoint(_font, C.int(r), _pos, C.float(run.size), _c)
+		}
+		// Draw decorations.
+		thick := math.Max(1, math.Round(run.size/16))
+		if run.span.Underline {
+			_rect := C.Rectangle{
+				x:      C.float(run.xs[0]),
+				y:      C.float(run.y + run.size - thick),
+				width:  C.float(run.end - run.xs[0]),
+				height: C.float(thick),
+			}
+			C.DrawRectangleRec(_rect, _c)
+		}
+		if run.span.Strikethrough {
+			_rect := C.Rectangle{
+				x:      C.float(run.xs[0]),
+				y:      C.float(run.y + (run.size-thick)/2),
+				width:  C.float(run.end - run.xs[0]),
+				height: C.float(thick),
+			}
+			C.DrawRectangleRec(_rect, _c)
+		}
+	}
+	C.rlPopMatrix()
+}
+
+// ### [ Helper functions ] ####################################################
+
+// size returns the font size of the span.
+func (span *Span) size() int {
+	if span.Size == 0 {
+		return defaultTextSize
+	}
+	return span.Size
+}
+
+// color returns the text colour of the span.
+func (span *Span) color() color.Color {
+	if span.Color == nil {
+		return color.Black
+	}
+	return span.Color
+}
+
+// raylibFont returns the raylib font used to render the run, rasterizing glyphs
+// of the run not yet present in the atlas of dynamic fonts.
+func (run *richRun) raylibFont() C.Font {
+	font := run.span.Font
+	if font == nil {
+		return DefaultFont()._font
+	}
+	if font.dyn != nil && (!run.rasterized || run.dynGen != font.dyn.gen) {
+		// Rasterize glyphs of the run not yet present in the atlas of the
+		// dynamic font.
+		font.ensureGlyphs(string(run.runes))
+		run.rasterized = true
+		run.dynGen = font.dyn.gen
+	}
+	return font._font
+}
+
+// raylibFontOf returns the raylib font used to render the given string using
+// the specified font (or the default font if nil), rasterizing glyphs of the
+// string not yet present in the atlas of dynamic fonts.
+func raylibFontOf(font *Font, s string) C.Font {
+	if font == nil {
+		return DefaultFont()._font
+	}
+	if font.dyn != nil {
+		font.ensureGlyphs(s)
+	}
+	return font._font
+}
@@ -0,0 +1,120 @@
+package window
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	mono := &Font{}
+	fonts := map[string]*Font{"mono": mono}
+	red := color.NRGBA{R: 0xFF, A: 0xFF}
+	golden := []struct {
+		markup string
+		want   []Span
+		err    bool
+	}{
+		// Plain text.
+		{markup: "", want: nil},
+		{markup: "foo", want: []Span{{Text: "foo"}}},
+		// Escaped '['.
+		{markup: "[[foo]", want: []Span{{Text: "[foo]"}}},
+		{markup: "a[[[u]b[/u]", want: []Span{{Text: "a["}, {Text: "b", Underline: true}}},
+		// Tags.
+		{markup: "[color=#f00]foo[/color]bar", want: []Span{{Text: "foo", Color: red}, {Text: "bar"}}},
+		{markup: "[size=20]foo[/size]", want: []Span{{Text: "foo", Size: 20}}},
+		{markup: "[font=mono]foo[/font]", want: []Span{{Text: "foo", Font: mono}}},
+		{markup: "[s]foo[/s]", want: []Span{{Text: "foo", Strikethrough: true}}},
+		// Nested tags.
+		{
+			markup: "[u]a[size=20]b[u]c[/u][/size]d[/u]e",
+			want: []Span{
+				{Text: "a", Underline: true},
+				{Text: "b", Size: 20, Underline: true},
+				{Text: "c", Size: 20, Underline: true},
+				{Text: "d", Underline: true},
+				{Text: "e"},
+			},
+		},
+		{
+			markup: "[size=10][size=20]a[/size]b[/size]",
+			want:   []Span{{Text: "a", Size: 20}, {Text: "b", Size: 10}},
+		},
+		// Malformed tags.
+		{markup: "[u", err: true},
+		{markup: "foo[color=#f00", err: true},
+		{markup: "[u=1]foo[/u]", err: true},
+		{markup: "[color]foo[/color]", err: true},
+		{markup: "[color=red]foo[/color]", err: true},
+		{markup: "[size=0]foo[/size]", err: true},
+		{markup: "[size=x]foo[/size]", err: true},
+		{markup: "[font=serif]foo[/font]", err: true},
+		{markup: "[b]foo[/b]", err: true},
+		{markup: "[]", err: true},
+		// Unmatched closing tags.
+		{markup: "foo[/u]", err: true},
+		{markup: "[u]foo[/s]", err: true},
+		{markup: "[color=#f00]foo[/color][/color]", err: true},
+	}
+	for _, g := range golden {
+		got, err := ParseMarkup(g.markup, fonts)
+		if g.err {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", g.markup)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", g.markup, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("%q: spans mismatch; expected %+v, got %+v", g.markup, g.want, got)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	golden := []struct {
+		s    string
+		want color.Color
+		err  bool
+	}{
+		// #rgb
+		{s: "#f00", want: color.NRGBA{R: 0xFF, A: 0xFF}},
+		{s: "#1a3", want: color.NRGBA{R: 0x11, G: 0xAA, B: 0x33, A: 0xFF}},
+		// #rgba
+		{s: "#f008", want: color.NRGBA{R: 0xFF, A: 0x88}},
+		// #rrggbb
+		{s: "#12ab3C", want: color.NRGBA{R: 0x12, G: 0xAB, B: 0x3C, A: 0xFF}},
+		// #rrggbbaa
+		{s: "#12ab3c80", want: color.NRGBA{R: 0x12, G: 0xAB, B: 0x3C, A: 0x80}},
+		{s: "#00000000", want: color.NRGBA{}},
+		// Invalid colours.
+		{s: "", err: true},
+		{s: "f00", err: true},
+		{s: "#", err: true},
+		{s: "#f0", err: true},
+		{s: "#f0000", err: true},
+		{s: "#f00000000", err: true},
+		{s: "#ggg", err: true},
+		{s: "#+f00000", err: true},
+	}
+	for _, g := range golden {
+		got, err := parseHexColor(g.s)
+		if g.err {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", g.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", g.s, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("%q: colour mismatch; expected %v, got %v", g.s, g.want, got)
+		}
+	}
+}
@@ -1,45 +1,349 @@
 package window
 
-// #include <stdlib.h>
+// #include <stdarg.h>
+// #include <stdio.h>
 // #include <raylib.h>
+// #include <rlgl.h>
+//
+// // shaderLog holds raylib log messages captured while loading shaders.
+// static char shaderLog[8192];
+// static size_t shaderLogLen;
+//
+// // captureShaderLog appends the given raylib log message to shaderLog.
+// static void captureShaderLog(int logLevel, const char *text, va_list args) {
+// 	if (logLevel < LOG_WARNING || shaderLogLen >= sizeof(shaderLog)-1) {
+// 		return;
+// 	}
+// 	size_t size = sizeof(shaderLog) - shaderLogLen;
+// 	int n = vsnprintf(shaderLog + shaderLogLen, size - 1, text, args);
+// 	if (n < 0) {
+// 		return;
+// 	}
+// 	shaderLogLen += (size_t)n < size - 1 ? (size_t)n : size - 2;
+// 	shaderLog[shaderLogLen++] = '\n';
+// 	shaderLog[shaderLogLen] = '\0';
+// }
+//
+// // beginShaderLog starts capturing raylib warning and error log messages.
+// static void beginShaderLog(void) {
+// 	shaderLogLen = 0;
+// 	shaderLog[0] = '\0';
+// 	SetTraceLogLevel(LOG_WARNING);
+// 	SetTraceLogCallback(captureShaderLog);
+// }
+//
+// // endShaderLog stops capturing raylib log messages, restores the given log
+// // level, and returns the captured log messages.
+// static const char *endShaderLog(int logLevel) {
+// 	SetTraceLogCallback(NULL);
+// 	SetTraceLogLevel(logLevel);
+// 	return shaderLog;
+// }
 import "C"
 
 import (
 	"runtime"
+	"strings"
 	"unsafe"
+
+	"github.com/pkg/errors"
 )
 
 // Shader holds a vertex or fragment shader.
 type Shader struct {
 	// underlying raylib shader.
 	_shader C.Shader
+	// Uniform locations, indexed by uniform name.
+	locs map[string]C.int
+	// Vertex attribute locations, indexed by attribute name.
+	attribLocs map[string]C.int
+	// Textures bound to sampler2D uniforms, indexed by uniform name; kept to
+	// prevent textures in use from being unloaded.
+	textures map[string]*Texture
+	// Watch state of shaders reloaded on change; or nil if not watched.
+	watch *shaderWatch
 }
 
-// LoadShader loads the given vertex and fragment shader.
+// LoadShader loads the given vertex and fragment shader. The default vertex or
+// fragment shader is used if vsPath or fsPath is empty, respectively.
+//
+// The returned error wraps ErrNotFound if a shader file does not exist, and
+// ErrCompile (as a *CompileError) if the shader program could not be compiled
+// or linked.
+//
+// Note: a finalizer is registered to unload the shader.
 func LoadShader(vsPath, fsPath string) (*Shader, error) {
-	_vsPath := C.CString(vsPath)
-	defer C.free(unsafe.Pointer(_vsPath))
-	_fsPath := C.CString(fsPath)
-	defer C.free(unsafe.Pointer(_fsPath))
-	_shader := C.LoadShader(_vsPath, _fsPath)
-	// TODO: figure out how to check error.
-	shader := &Shader{
-		_shader: _shader,
+	checkMainThread()
+	_shader, err := loadShader(vsPath, fsPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
-	// Set finalizer to free shader.
-	free := func(obj any) {
-		C.UnloadShader(_shader)
+	return newShader(_shader), nil
+}
+
+// LoadShaderFromMemory compiles the given vertex and fragment shader source
+// code. The default vertex or fragment shader is used if vsCode or fsCode is
+// empty, respectively.
+//
+// The returned error wraps ErrCompile (as a *CompileError) if the shader
+// program could not be compiled or linked.
+//
+// Note: a finalizer is registered to unload the shader.
+func LoadShaderFromMemory(vsCode, fsCode string) (*Shader, error) {
+	checkMainThread()
+	var _vsCode, _fsCode *C.char
+	if len(vsCode) > 0 {
+		_vsCode = cString(vsCode)
+		defer freeCString(_vsCode)
 	}
-	runtime.SetFinalizer(shader, free)
-	return shader, nil
+	if len(fsCode) > 0 {
+		_fsCode = cString(fsCode)
+		defer freeCString(_fsCode)
+	}
+	C.beginShaderLog()
+	_shader := C.LoadShaderFromMemory(_vsCode, _fsCode)
+	log := C.GoString(C.endShaderLog(C.int(logLevel)))
+	// Note: raylib falls back to the default shader on error.
+	if _shader.id == C.rlGetShaderIdDefault() && (_vsCode != nil || _fsCode != nil) {
+		err := &CompileError{Log: strings.TrimSpace(log)}
+		return nil, errors.Wrap(err, "unable to load shader from memory")
+	}
+	return newShader(_shader), nil
 }
 
 // Enable enables drawing of the shader.
 func (shader *Shader) Enable() {
+	checkMainThread()
 	C.BeginShaderMode(shader._shader)
 }
 
 // Disable disables drawing of the shader.
 func (shader *Shader) Disable() {
+	checkMainThread()
 	C.EndShaderMode()
 }
+
+// Free releases the GPU resources of the shader, and stops watching its source
+// files. The shader must not be used after it has been freed. Freeing a shader
+// more than once has no effect.
+//
+// Note: the shader is otherwise freed by Window.Display once it has been
+// garbage collected.
+func (shader *Shader) Free() {
+	checkMainThread()
+	if shader._shader.id == 0 {
+		return
+	}
+	if shader.watch != nil {
+		shader.Unwatch()
+	}
+	runtime.SetFinalizer(shader, nil)
+	// Draw pending draw operations using the shader before unloading it.
+	flushBatch()
+	C.UnloadShader(shader._shader)
+	shader._shader = C.Shader{}
+	clear(shader.textures)
+}
+
+// Location returns the location of the given uniform of the shader, or -1 if
+// not present. Locations are cached per uniform name.
+func (shader *Shader) Location(name string) int {
+	checkMainThread()
+	return int(shader.location(name))
+}
+
+// AttribLocation returns the location of the given vertex attribute of the
+// shader, or -1 if not present. Locations are cached per attribute name.
+func (shader *Shader) AttribLocation(name string) int {
+	checkMainThread()
+	if loc, ok := shader.attribLocs[name]; ok {
+		return int(loc)
+	}
+	_name := cString(name)
+	defer freeCString(_name)
+	loc := C.GetShaderLocationAttrib(shader._shader, _name)
+	shader.attribLocs[name] = loc
+	return int(loc)
+}
+
+// --- [ uniforms ] ------------------------------------------------------------
+
+// Note: uniforms not present in the shader (e.g. removed by the shader
+// compiler as they are unused) are silently ignored by the uniform setters.
+
+// SetFloat sets the value of the given float uniform.
+func (shader *Shader) SetFloat(name string, v float32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetFloat(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v), C.SHADER_UNIFORM_FLOAT)
+}
+
+// SetVec2 sets the value of the given vec2 uniform.
+func (shader *Shader) SetVec2(name string, v [2]float32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetVec2(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_VEC2)
+}
+
+// SetVec3 sets the value of the given vec3 uniform.
+func (shader *Shader) SetVec3(name string, v [3]float32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetVec3(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_VEC3)
+}
+
+// SetVec4 sets the value of the given vec4 uniform.
+func (shader *Shader) SetVec4(name string, v [4]float32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetVec4(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_VEC4)
+}
+
+// SetInt sets the value of the given int uniform.
+func (shader *Shader) SetInt(name string, v int32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetInt(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v), C.SHADER_UNIFORM_INT)
+}
+
+// SetIVec2 sets the value of the given ivec2 uniform.
+func (shader *Shader) SetIVec2(name string, v [2]int32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetIVec2(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_IVEC2)
+}
+
+// SetIVec3 sets the value of the given ivec3 uniform.
+func (shader *Shader) SetIVec3(name string, v [3]int32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetIVec3(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_IVEC3)
+}
+
+// SetIVec4 sets the value of the given ivec4 uniform.
+func (shader *Shader) SetIVec4(name string, v [4]int32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetIVec4(name, v) })
+	shader.setValue(name, unsafe.Pointer(&v[0]), C.SHADER_UNIFORM_IVEC4)
+}
+
+// SetMat4 sets the value of the given mat4 uniform, specified in column-major
+// order.
+func (shader *Shader) SetMat4(name string, m [16]float32) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetMat4(name, m) })
+	loc := shader.location(name)
+	if loc < 0 {
+		return
+	}
+	// Note: the field mN of raylib matrices corresponds to element N in
+	// column-major order.
+	_m := C.Matrix{
+		m0: C.float(m[0]), m4: C.float(m[4]), m8: C.float(m[8]), m12: C.float(m[12]),
+		m1: C.float(m[1]), m5: C.float(m[5]), m9: C.float(m[9]), m13: C.float(m[13]),
+		m2: C.float(m[2]), m6: C.float(m[6]), m10: C.float(m[10]), m14: C.float(m[14]),
+		m3: C.float(m[3]), m7: C.float(m[7]), m11: C.float(m[11]), m15: C.float(m[15]),
+	}
+	C.SetShaderValueMatrix(shader._shader, loc, _m)
+}
+
+// SetTexture binds the given texture to the given sampler2D uniform.
+//
+// Note: the entire texture is bound for sub-images.
+func (shader *Shader) SetTexture(name string, tex *Texture) {
+	checkMainThread()
+	shader.record(name, func(shader *Shader) { shader.SetTexture(name, tex) })
+	loc := shader.location(name)
+	if loc < 0 {
+		return
+	}
+	C.SetShaderValueTexture(shader._shader, loc, tex.raylibTexture())
+	shader.textures[name] = tex
+}
+
+// ### [ Helper functions ] ####################################################
+
+// loadShader loads the given vertex and fragment shader. The default vertex or
+// fragment shader is used if vsPath or fsPath is empty, respectively.
+func loadShader(vsPath, fsPath string) (C.Shader, error) {
+	var _vsPath, _fsPath *C.char
+	if len(vsPath) > 0 {
+		if err := checkFile(vsPath); err != nil {
+			return C.Shader{}, errors.WithStack(err)
+		}
+		_vsPath = cString(vsPath)
+		defer freeCString(_vsPath)
+	}
+	if len(fsPath) > 0 {
+		if err := checkFile(fsPath); err != nil {
+			return C.Shader{}, errors.WithStack(err)
+		}
+		_fsPath = cString(fsPath)
+		defer freeCString(_fsPath)
+	}
+	C.beginShaderLog()
+	_shader := C.LoadShader(_vsPath, _fsPath)
+	log := C.GoString(C.endShaderLog(C.int(logLevel)))
+	// Note: raylib falls back to the default shader on error.
+	if _shader.id == C.rlGetShaderIdDefault() && (_vsPath != nil || _fsPath != nil) {
+		err := &CompileError{Log: strings.TrimSpace(log)}
+		return C.Shader{}, errors.Wrapf(err, "unable to load shader (vertex %q, fragment %q)", vsPath, fsPath)
+	}
+	return _shader, nil
+}
+
+// newShader returns a new shader based on the given raylib shader.
+//
+// Note: a finalizer is registered to unload the shader.
+func newShader(_shader C.Shader) *Shader {
+	shader := &Shader{
+		_shader:    _shader,
+		locs:       make(map[string]C.int),
+		attribLocs: make(map[string]C.int),
+		textures:   make(map[string]*Texture),
+	}
+	// Set finalizer to free shader.
+	//
+	// Note: the underlying raylib shader is replaced when watched shaders are
+	// reloaded.
+	free := func(shader *Shader) {
+		_shader := shader._shader
+		deferRelease(func() {
+			C.UnloadShader(_shader)
+		})
+	}
+	runtime.SetFinalizer(shader, free)
+	return shader
+}
+
+// record records the given uniform setter of watched shaders, to be re-applied
+// when the shader is reloaded.
+func (shader *Shader) record(name string, set func(shader *Shader)) {
+	if shader.watch == nil {
+		return
+	}
+	shader.watch.uniforms[name] = set
+}
+
+// location returns the location of the given uniform of the shader, or -1 if
+// not present.
+func (shader *Shader) location(name string) C.int {
+	if loc, ok := shader.locs[name]; ok {
+		return loc
+	}
+	_name := cString(name)
+	defer freeCString(_name)
+	loc := C.GetShaderLocation(shader._shader, _name)
+	shader.locs[name] = loc
+	return loc
+}
+
+// setValue sets the value of the given uniform, of the specified raylib uniform
+// data type.
+func (shader *Shader) setValue(name string, value unsafe.Pointer, uniformType C.int) {
+	loc := shader.location(name)
+	if loc < 0 {
+		return
+	}
+	C.SetShaderValue(shader._shader, loc, value, uniformType)
+}
@@ -0,0 +1,311 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"image"
+	"image/color"
+	"slices"
+)
+
+// A ShapeDrawer draws shape primitives onto a canvas. It is implemented by
+// windows and render targets, and embedded in the Canvas interface.
+type ShapeDrawer interface {
+	// DrawPixel draws a single pixel at the given point.
+	DrawPixel(pt image.Point, col color.Color)
+	// DrawLine draws a line between the given points, of the specified
+	// thickness in pixels.
+	DrawLine(p1, p2 image.Point, thick float64, col color.Color)
+	// DrawLineStrip draws a sequence of connected lines through the given
+	// points.
+	DrawLineStrip(points []image.Point, col color.Color)
+	// DrawBezier draws a line between the given points using cubic-bezier ease
+	// in-out.
+	DrawBezier(p1, p2 image.Point, thick float64, col color.Color)
+	// DrawBezierQuad draws a quadratic bezier curve with a control point.
+	DrawBezierQuad(p1, p2, control image.Point, thick float64, col color.Color)
+	// DrawBezierCubic draws a cubic bezier curve with two control points.
+	DrawBezierCubic(p1, p2, control1, control2 image.Point, thick float64, col color.Color)
+	// FillRect draws a filled rectangle.
+	FillRect(rect image.Rectangle, col color.Color)
+	// StrokeRect draws the outline of a rectangle.
+	StrokeRect(rect image.Rectangle, thick float64, col color.Color)
+	// FillRoundedRect draws a filled rectangle with rounded corners.
+	FillRoundedRect(rect image.Rectangle, roundness float64, col color.Color)
+	// StrokeRoundedRect draws the outline of a rectangle with rounded corners.
+	StrokeRoundedRect(rect image.Rectangle, roundness, thick float64, col color.Color)
+	// FillRectGradient draws a filled rectangle with the colours of its corners
+	// interpolated.
+	FillRectGradient(rect image.Rectangle, topLeft, bottomLeft, bottomRight, topRight color.Color)
+	// FillRectGradientV draws a rectangle filled with a vertical gradient.
+	FillRectGradientV(rect image.Rectangle, top, bottom color.Color)
+	// FillRectGradientH draws a rectangle filled with a horizontal gradient.
+	FillRectGradientH(rect image.Rectangle, left, right color.Color)
+	// FillCircle draws a filled circle.
+	FillCircle(center image.Point, radius float64, col color.Color)
+	// StrokeCircle draws the outline of a circle.
+	StrokeCircle(center image.Point, radius float64, col color.Color)
+	// FillEllipse draws a filled ellipse.
+	FillEllipse(center image.Point, radiusH, radiusV float64, col color.Color)
+	// StrokeEllipse draws the outline of an ellipse.
+	StrokeEllipse(center image.Point, radiusH, radiusV float64, col color.Color)
+	// FillRing draws a filled ring (or ring segment).
+	FillRing(center image.Point, innerRadius, outerRadius, startAngle, endAngle float64, col color.Color)
+	// StrokeRing draws the outline of a ring (or ring segment).
+	StrokeRing(center image.Point, innerRadius, outerRadius, startAngle, endAngle float64, col color.Color)
+	// FillTriangle draws a filled triangle.
+	FillTriangle(p1, p2, p3 image.Point, col color.Color)
+	// StrokeTriangle draws the outline of a triangle.
+	StrokeTriangle(p1, p2, p3 image.Point, col color.Color)
+	// FillTriangleFan draws a filled triangle fan.
+	FillTriangleFan(points []image.Point, col color.Color)
+	// FillTriangleStrip draws a filled triangle strip.
+	FillTriangleStrip(points []image.Point, col color.Color)
+	// FillPolygon draws a filled regular polygon.
+	FillPolygon(center image.Point, sides int, radius, rotation float64, col color.Color)
+	// StrokePolygon draws the outline of a regular polygon.
+	StrokePolygon(center image.Point, sides int, radius, rotation, thick float64, col color.Color)
+	// DrawPolygon draws a filled convex polygon of the given vertices.
+	DrawPolygon(points []image.Point, col color.Color)
+}
+
+// --- [ pixels and lines ] ----------------------------------------------------
+
+// DrawPixel draws a single pixel at the given point.
+func (c *canvas) DrawPixel(pt image.Point, col color.Color) {
+	c.bind()
+	C.DrawPixelV(vector2FromPoint(pt), raylibColor(col))
+}
+
+// DrawLine draws a line between the given points, of the specified thickness
+// in pixels.
+func (c *canvas) DrawLine(p1, p2 image.Point, thick float64, col color.Color) {
+	c.bind()
+	C.DrawLineEx(vector2FromPoint(p1), vector2FromPoint(p2), C.float(thick), raylibColor(col))
+}
+
+// DrawLineStrip draws a sequence of connected lines through the given points.
+func (c *canvas) DrawLineStrip(points []image.Point, col color.Color) {
+	if len(points) == 0 {
+		return
+	}
+	c.bind()
+	_points := vector2sFromPoints(points)
+	C.DrawLineStrip(&_points[0], C.int(len(_points)), raylibColor(col))
+}
+
+// DrawBezier draws a line between the given points using cubic-bezier ease
+// in-out, of the specified thickness in pixels.
+func (c *canvas) DrawBezier(p1, p2 image.Point, thick float64, col color.Color) {
+	c.bind()
+	C.DrawLineBezier(vector2FromPoint(p1), vector2FromPoint(p2), C.float(thick), raylibColor(col))
+}
+
+// DrawBezierQuad draws a quadratic bezier curve between the given points,
+// using the specified control point.
+func (c *canvas) DrawBezierQuad(p1, p2, control image.Point, thick float64, col color.Color) {
+	c.bind()
+	C.DrawLineBezierQuad(vector2FromPoint(p1), vector2FromPoint(p2), vector2FromPoint(control), C.float(thick), raylibColor(col))
+}
+
+// DrawBezierCubic draws a cubic bezier curve between the given points, using
+// the specified control points of p1 and p2, respectively.
+func (c *canvas) DrawBezierCubic(p1, p2, control1, control2 image.Point, thick float64, col color.Color) {
+	c.bind()
+	C.DrawLineBezierCubic(vector2FromPoint(p1), vector2FromPoint(p2), vector2FromPoint(control1), vector2FromPoint(control2), C.float(thick), raylibColor(col))
+}
+
+// --- [ rectangles ] ----------------------------------------------------------
+
+// FillRect draws a filled rectangle.
+func (c *canvas) FillRect(rect image.Rectangle, col color.Color) {
+	c.bind()
+	C.DrawRectangleRec(raylibRectangle(rect), raylibColor(col))
+}
+
+// StrokeRect draws the outline of a rectangle, of the specified thickness in
+// pixels.
+func (c *canvas) StrokeRect(rect image.Rectangle, thick float64, col color.Color) {
+	c.bind()
+	C.DrawRectangleLinesEx(raylibRectangle(rect), C.float(thick), raylibColor(col))
+}
+
+// FillRoundedRect draws a filled rectangle with rounded corners. The roundness,
+// in the range [0, 1], specifies the corner radius relative to the shortest
+// side of the rectangle.
+func (c *canvas) FillRoundedRect(rect image.Rectangle, roundness float64, col color.Color) {
+	c.bind()
+	C.DrawRectangleRounded(raylibRectangle(rect), C.float(roundness), defaultSegments, raylibColor(col))
+}
+
+// StrokeRoundedRect draws the outline of a rectangle with rounded corners, of
+// the specified thickness in pixels. The roundness, in the range [0, 1],
+// specifies the corner radius relative to the shortest side of the rectangle.
+func (c *canvas) StrokeRoundedRect(rect image.Rectangle, roundness, thick float64, col color.Color) {
+	c.bind()
+	C.DrawRectangleRoundedLines(raylibRectangle(rect), C.float(roundness), defaultSegments, C.float(thick), raylibColor(col))
+}
+
+// FillRectGradient draws a filled rectangle with the colours of its corners
+// interpolated.
+func (c *canvas) FillRectGradient(rect image.Rectangle, topLeft, bottomLeft, bottomRight, topRight color.Color) {
+	c.bind()
+	C.DrawRectangleGradientEx(raylibRectangle(rect), raylibColor(topLeft), raylibColor(bottomLeft), raylibColor(bottomRight), raylibColor(topRight))
+}
+
+// FillRectGradientV draws a filled rectangle with a vertical gradient, from the
+// top colour to the bottom colour.
+func (c *canvas) FillRectGradientV(rect image.Rectangle, top, bottom color.Color) {
+	c.FillRectGradient(rect, top, bottom, bottom, top)
+}
+
+// FillRectGradientH draws a filled rectangle with a horizontal gradient, from
+// the left colour to the right colour.
+func (c *canvas) FillRectGradientH(rect image.Rectangle, left, right color.Color) {
+	c.FillRectGradient(rect, left, left, right, right)
+}
+
+// --- [ circles and ellipses ] ------------------------------------------------
+
+// FillCircle draws a filled circle.
+func (c *canvas) FillCircle(center image.Point, radius float64, col color.Color) {
+	c.bind()
+	C.DrawCircleV(vector2FromPoint(center), C.float(radius), raylibColor(col))
+}
+
+// StrokeCircle draws the outline of a circle.
+func (c *canvas) StrokeCircle(center image.Point, radius float64, col color.Color) {
+	c.bind()
+	C.DrawCircleLines(C.int(center.X), C.int(center.Y), C.float(radius), raylibColor(col))
+}
+
+// FillEllipse draws a filled ellipse, of the specified horizontal and vertical
+// radius.
+func (c *canvas) FillEllipse(center image.Point, radiusH, radiusV float64, col color.Color) {
+	c.bind()
+	C.DrawEllipse(C.int(center.X), C.int(center.Y), C.float(radiusH), C.float(radiusV), raylibColor(col))
+}
+
+// StrokeEllipse draws the outline of an ellipse, of the specified horizontal
+// and vertical radius.
+func (c *canvas) StrokeEllipse(center image.Point, radiusH, radiusV float64, col color.Color) {
+	c.bind()
+	C.DrawEllipseLines(C.int(center.X), C.int(center.Y), C.float(radiusH), C.float(radiusV), raylibColor(col))
+}
+
+// FillRing draws a filled ring (or ring segment) between the inner and outer
+// radius, from the start angle to the end angle in degrees.
+func (c *canvas) FillRing(center image.Point, innerRadius, outerRadius, startAngle, endAngle float64, col color.Color) {
+	c.bind()
+	C.DrawRing(vector2FromPoint(center), C.float(innerRadius), C.float(outerRadius), C.float(startAngle), C.float(endAngle), defaultSegments, raylibColor(col))
+}
+
+// StrokeRing draws the outline of a ring (or ring segment) between the inner
+// and outer radius, from the start angle to the end angle in degrees.
+func (c *canvas) StrokeRing(center image.Point, innerRadius, outerRadius, startAngle, endAngle float64, col color.Color) {
+	c.bind()
+	C.DrawRingLines(vector2FromPoint(center), C.float(innerRadius), C.float(outerRadius), C.float(startAngle), C.float(endAngle), defaultSegments, raylibColor(col))
+}
+
+// --- [ triangles and polygons ] ----------------------------------------------
+
+// FillTriangle draws a filled triangle.
+//
+// Note: the vertices must be specified in counter-clockwise order.
+func (c *canvas) FillTriangle(p1, p2, p3 image.Point, col color.Color) {
+	c.bind()
+	C.DrawTriangle(vector2FromPoint(p1), vector2FromPoint(p2), vector2FromPoint(p3), raylibColor(col))
+}
+
+// StrokeTriangle draws the outline of a triangle.
+//
+// Note: the vertices must be specified in counter-clockwise order.
+func (c *canvas) StrokeTriangle(p1, p2, p3 image.Point, col color.Color) {
+	c.bind()
+	C.DrawTriangleLines(vector2FromPoint(p1), vector2FromPoint(p2), vector2FromPoint(p3), raylibColor(col))
+}
+
+// FillTriangleFan draws a filled triangle fan; the first point is the shared
+// center vertex.
+//
+// Note: the vertices must be specified in counter-clockwise order.
+func (c *canvas) FillTriangleFan(points []image.Point, col color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	c.bind()
+	_points := vector2sFromPoints(points)
+	C.DrawTriangleFan(&_points[0], C.int(len(_points)), raylibColor(col))
+}
+
+// FillTriangleStrip draws a filled triangle strip.
+func (c *canvas) FillTriangleStrip(points []image.Point, col color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	c.bind()
+	_points := vector2sFromPoints(points)
+	C.DrawTriangleStrip(&_points[0], C.int(len(_points)), raylibColor(col))
+}
+
+// FillPolygon draws a filled regular polygon of the given number of sides,
+// radius and rotation in degrees.
+func (c *canvas) FillPolygon(center image.Point, sides int, radius, rotation float64, col color.Color) {
+	c.bind()
+	C.DrawPoly(vector2FromPoint(center), C.int(sides), C.float(radius), C.float(rotation), raylibColor(col))
+}
+
+// StrokePolygon draws the outline of a regular polygon of the given number of
+// sides, radius and rotation in degrees, of the specified thickness in pixels.
+func (c *canvas) StrokePolygon(center image.Point, sides int, radius, rotation, thick float64, col color.Color) {
+	c.bind()
+	C.DrawPolyLinesEx(vector2FromPoint(center), C.int(sides), C.float(radius), C.float(rotation), C.float(thick), raylibColor(col))
+}
+
+// DrawPolygon draws a filled convex polygon of the given vertices, specified in
+// either clockwise or counter-clockwise order.
+//
+// Note: the polygon is drawn as a triangle fan from the first vertex, and must
+// thus be convex.
+func (c *canvas) DrawPolygon(points []image.Point, col color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	c.bind()
+	_points := vector2sFromPoints(points)
+	// raylib culls triangles not specified in counter-clockwise order (on
+	// screen, with the y-axis pointing down); i.e. of positive signed area.
+	if signedArea(points) > 0 {
+		slices.Reverse(_points)
+	}
+	C.DrawTriangleFan(&_points[0], C.int(len(_points)), raylibColor(col))
+}
+
+// ### [ Helper functions ] ####################################################
+
+// signedArea returns twice the signed area of the polygon of the given
+// vertices (shoelace formula), which is negative for vertices in
+// counter-clockwise order on screen.
+func signedArea(points []image.Point) int {
+	area := 0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return area
+}
+
+// defaultSegments specifies the number of segments used to draw rounded
+// shapes; 0 lets raylib compute the number of segments based on size.
+const defaultSegments = 0
+
+// vector2sFromPoints converts the given Go points to the corresponding raylib
+// vector2s.
+func vector2sFromPoints(points []image.Point) []C.Vector2 {
+	_points := make([]C.Vector2, len(points))
+	for i, pt := range points {
+		_points[i] = vector2FromPoint(pt)
+	}
+	return _points
+}
@@ -0,0 +1,27 @@
+package window
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSignedArea(t *testing.T) {
+	golden := []struct {
+		points []image.Point
+		want   int
+	}{
+		// Counter-clockwise on screen (y-axis pointing down).
+		{points: []image.Point{{0, 0}, {0, 10}, {10, 10}}, want: -100},
+		{points: []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}}, want: -200},
+		// Clockwise on screen.
+		{points: []image.Point{{0, 0}, {10, 10}, {0, 10}}, want: 100},
+		{points: []image.Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, want: 200},
+		// Degenerate.
+		{points: []image.Point{{0, 0}, {5, 5}, {10, 10}}, want: 0},
+	}
+	for _, g := range golden {
+		if got := signedArea(g.points); got != g.want {
+			t.Errorf("%v: signed area mismatch; expected %d, got %d", g.points, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+
+	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
+)
+
+// A RenderTarget is an offscreen draw target backed by a texture. It implements
+// the Canvas and wandi.Image interfaces, and may thus be drawn onto windows and
+// other render targets.
+type RenderTarget struct {
+	// Draw operations onto the underlying raylib render texture.
+	canvas
+}
+
+// NewRenderTarget returns a new offscreen render target of the specified
+// dimensions.
+//
+// The returned error wraps ErrGPUUpload if the render target could not be
+// created on the GPU.
+//
+// Note: a finalizer is registered to unload the render target.
+func NewRenderTarget(width, height int) (*RenderTarget, error) {
+	checkMainThread()
+	_rt := C.LoadRenderTexture(C.int(width), C.int(height))
+	if _rt.id == 0 || _rt.texture.id == 0 {
+		return nil, errors.Wrapf(ErrGPUUpload, "unable to create render target of dimensions %dx%d", width, height)
+	}
+	rt := &RenderTarget{
+		canvas: canvas{
+			_rt: _rt,
+		},
+	}
+	// Register finalizer to unload render target.
+	free := func(rt *RenderTarget) {
+		_rt := rt._rt
+		deferRelease(func() {
+			C.UnloadRenderTexture(_rt)
+		})
+	}
+	runtime.SetFinalizer(rt, free)
+	return rt, nil
+}
+
+// Free releases the GPU resources of the render target. The render target must
+// not be used after it has been freed. Freeing a render target more than once
+// has no effect.
+//
+// Note: the render target is otherwise freed by Window.Display once it has been
+// garbage collected.
+func (rt *RenderTarget) Free() {
+	checkMainThread()
+	if rt._rt.id == 0 {
+		return
+	}
+	runtime.SetFinalizer(rt, nil)
+	if activeTarget.id == rt._rt.id {
+		bindTarget(C.RenderTexture2D{})
+	}
+	// Draw pending draw operations using the render target before unloading it.
+	flushBatch()
+	C.UnloadRenderTexture(rt._rt)
+	rt._rt = C.RenderTexture2D{}
+}
+
+// Width returns the width of the render target.
+func (rt *RenderTarget) Width() int {
+	return int(rt._rt.texture.width)
+}
+
+// Height returns the height of the render target.
+func (rt *RenderTarget) Height() int {
+	return int(rt._rt.texture.height)
+}
+
+// Draw draws the entire src image onto the render target starting at the
+// destination point dp.
+func (rt *RenderTarget) Draw(dp image.Point, src wandi.Image) error {
+	sr := image.Rect(0, 0, src.Width(), src.Height())
+	return rt.DrawRect(dp, src, sr)
+}
+
+// DrawRect draws a subset of the src image, as defined by the source rectangle
+// sr, onto the render target starting at the destination point dp.
+func (rt *RenderTarget) DrawRect(dp image.Point, src wandi.Image, sr image.Rectangle) error {
+	rt.bind()
+	return drawRect(dp, src, sr)
+}
+
+// DrawEx draws the src image onto the render target starting at the
+// destination point dp, as transformed and tinted by the given draw options.
+func (rt *RenderTarget) DrawEx(dp image.Point, src wandi.Image, opts *DrawOptions) error {
+	rt.bind()
+	return drawEx(dp, src, opts)
+}
+
+// Clear clears the entire render target with the given color. Only the current
+// clip rectangle is cleared if set.
+func (rt *RenderTarget) Clear(c color.Color) {
+	rt.bind()
+	C.ClearBackground(raylibColor(c))
+}
+
+// Display finishes drawing what has been rendered so far to the render target,
+// making it available for drawing onto other draw targets.
+//
+// Note: drawing onto another draw target implicitly finishes drawing to the
+// render target.
+func (rt *RenderTarget) Display() {
+	checkMainThread()
+	if activeTarget.id == rt._rt.id {
+		bindTarget(C.RenderTexture2D{})
+	}
+}
+
+// Ensure that RenderTarget implements Canvas and wandi.Image.
+var (
+	_ Canvas      = (*RenderTarget)(nil)
+	_ wandi.Image = (*RenderTarget)(nil)
+)
@@ -1,28 +1,53 @@
 package window
 
-// #include <stdlib.h>
 // #include <raylib.h>
 import "C"
 
 import (
 	"image/color"
-	"unsafe"
+	"math"
 )
 
-// defaultSpacing specifies the default font spacing.
-const defaultSpacing = 1
+const (
+	// defaultSpacing specifies the default letter spacing in pixels.
+	defaultSpacing = 1
+	// defaultTextSize specifies the default font size in pixels of text.
+	defaultTextSize = 12
+)
 
 // Text represent a graphical text entry with a specific font, font size, and
 // colour. It implements the wandi.Image interface.
+//
+// The text is laid out into lines at explicit newlines, and optionally wrapped
+// to a maximum width, aligned and truncated to a maximum number of lines.
 type Text struct {
-	// Font to use for rendering.
+	// Font to use for rendering; or nil to use the default font.
 	font *Font
 	// Text string.
-	_str *C.char
+	//
+	// Note: the text string is kept in Go memory, as text is laid out and drawn
+	// one character at a time.
+	str string
 	// Font size in pixels.
 	fontSize int
 	// Text colour.
 	c color.Color
+	// Maximum line width in pixels; or 0 to not wrap lines.
+	maxWidth int
+	// Maximum number of lines; or 0 to not truncate text.
+	maxLines int
+	// Horizontal alignment of lines.
+	align Align
+	// Letter spacing in pixels.
+	spacing float64
+	// Line height relative to the font size.
+	lineHeight float64
+	// Layout of the text; or nil if not yet laid out.
+	lay *textLayout
+	// Dynamic font, and atlas generation of the dynamic font, for which the
+	// glyphs of the text have been rasterized; or nil if not yet rasterized.
+	dynFont *Font
+	dynGen  int
 }
 
 // NewText returns a new graphical text entry. The initial text, font, font
@@ -34,10 +59,13 @@ type Text struct {
 // black, respectively.
 func NewText(args ...interface{}) *Text {
 	// Create a text entry.
-	text := &Text{}
+	text := &Text{
+		spacing:    defaultSpacing,
+		lineHeight: defaultLineHeight,
+	}
 	// Set the default font, font size, and colour of the text.
-	text.SetFont(nil) // TODO: figure out how to handle default font.
-	text.SetFontSize(12)
+	text.SetFont(nil)
+	text.SetFontSize(defaultTextSize)
 	text.SetColor(color.Black)
 	// Customize the text, font, font size, and colour based on the provided
 	// arguments.
@@ -58,21 +86,73 @@ func NewText(args ...interface{}) *Text {
 
 // SetText sets the text of the text entry.
 func (text *Text) SetText(s string) {
-	if text._str != nil {
-		// free old string if present.
-		C.free(unsafe.Pointer(text._str))
-	}
-	text._str = C.CString(s)
+	text.str = s
+	// Rasterize glyphs of dynamic fonts on next use.
+	text.dynFont = nil
+	text.lay = nil
 }
 
-// SetFont sets the font of the text.
+// Free releases the text string and layout of the text entry. The text entry
+// must not be used after it has been freed. Freeing a text entry more than once
+// has no effect.
+//
+// Note: text entries hold no C memory, and are therefore released by the
+// garbage collector if not freed.
+func (text *Text) Free() {
+	text.str = ""
+	text.lay = nil
+	text.dynFont = nil
+}
+
+// SetFont sets the font of the text. A nil font selects the default font.
 func (text *Text) SetFont(font *Font) {
 	text.font = font
+	text.lay = nil
 }
 
 // SetFontSize sets the font size, in pixels, of the text.
 func (text *Text) SetFontSize(fontSize int) {
 	text.fontSize = fontSize
+	text.lay = nil
+}
+
+// SetMaxWidth sets the maximum line width, in pixels, of the text. Lines
+// exceeding the maximum width are wrapped at spaces, or between characters of
+// words wider than the maximum width. Lines are aligned within the maximum
+// width, which is thus the width of the text entry. The default is 0, which
+// does not wrap lines.
+func (text *Text) SetMaxWidth(maxWidth int) {
+	text.maxWidth = maxWidth
+	text.lay = nil
+}
+
+// SetMaxLines sets the maximum number of lines of the text. Text exceeding the
+// maximum number of lines is truncated, and an ellipsis is appended to the
+// last line. The default is 0, which does not truncate text.
+func (text *Text) SetMaxLines(maxLines int) {
+	text.maxLines = maxLines
+	text.lay = nil
+}
+
+// SetAlign sets the horizontal alignment of the lines of the text, within the
+// width of the text entry. The default alignment is AlignLeft.
+func (text *Text) SetAlign(align Align) {
+	text.align = align
+	text.lay = nil
+}
+
+// SetLetterSpacing sets the spacing, in pixels, between characters of the
+// text. The default letter spacing is 1.
+func (text *Text) SetLetterSpacing(spacing float64) {
+	text.spacing = spacing
+	text.lay = nil
+}
+
+// SetLineHeight sets the distance between the top of consecutive lines of the
+// text, relative to the font size. The default line height is 1.5.
+func (text *Text) SetLineHeight(lineHeight float64) {
+	text.lineHeight = lineHeight
+	text.lay = nil
 }
 
 // SetColor sets the colour of the text.
@@ -80,14 +160,33 @@ func (text *Text) SetColor(c color.Color) {
 	text.c = c
 }
 
-// Width returns the width of the text entry.
+// Width returns the width of the text entry, as laid out; i.e. the width of
+// its widest line, or the maximum width if specified.
 func (text *Text) Width() int {
-	_size := C.MeasureTextEx(text.font._font, text._str, C.float(text.fontSize), defaultSpacing)
-	return int(_size.x)
+	checkMainThread()
+	return int(math.Ceil(text.layout().width))
 }
 
-// Height returns the height of the text entry.
+// Height returns the height of the text entry, as laid out.
 func (text *Text) Height() int {
-	_size := C.MeasureTextEx(text.font._font, text._str, C.float(text.fontSize), defaultSpacing)
-	return int(_size.y)
+	checkMainThread()
+	return int(math.Ceil(text.layout().height))
+}
+
+// ### [ Helper functions ] ####################################################
+
+// raylibFont returns the raylib font used to render the text.
+func (text *Text) raylibFont() C.Font {
+	font := text.font
+	if font == nil {
+		return DefaultFont()._font
+	}
+	if font.dyn != nil && (text.dynFont != font || text.dynGen != font.dyn.gen) {
+		// Rasterize glyphs of the text not yet present in the atlas of the
+		// dynamic font.
+		font.ensureGlyphs(text.str)
+		text.dynFont = font
+		text.dynGen = font.dyn.gen
+	}
+	return font._font
 }
@@ -1,6 +1,5 @@
 package window
 
-// #include <stdlib.h>
 // #include <raylib.h>
 import "C"
 
@@ -15,24 +14,47 @@ import (
 
 	"github.com/mewpkg/clog"
 	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
 )
 
 // Texture represent a read-only texture. It implements the wandi.Image
-// interface.
+// interface. See MutableTexture for textures which may be updated.
 type Texture struct {
-	// underlying raylib texture.
+	// underlying raylib texture; or zero for sub-images, which use the raylib
+	// texture of their parent.
 	_tex C.Texture2D
+	// Bounds of the texture within the underlying raylib texture.
+	bounds image.Rectangle
+	// Texture owning the underlying raylib texture of sub-images; or nil if not
+	// a sub-image.
+	parent *Texture
 }
 
 // LoadTexture loads the provided file and converts it into a read-only texture.
 //
+// The returned error wraps ErrNotFound if the file does not exist, ErrDecode if
+// the image could not be decoded, and ErrGPUUpload if the texture could not be
+// uploaded to the GPU.
+//
 // Note: a finalizer is registered to unload the texture.
 func LoadTexture(path string) (*Texture, error) {
-	// Load the texture from file.
-	_path := C.CString(path)
-	defer C.free(unsafe.Pointer(_path))
-	_tex := C.LoadTexture(_path)
-	// TODO: figure out how to check error.
+	checkMainThread()
+	if err := checkFile(path); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Decode the image from file.
+	_path := cString(path)
+	defer freeCString(_path)
+	_img := C.LoadImage(_path)
+	if _img.data == nil {
+		return nil, errors.Wrapf(ErrDecode, "unable to load texture %q", path)
+	}
+	defer C.UnloadImage(_img)
+	// Upload the image to the GPU.
+	_tex := C.LoadTextureFromImage(_img)
+	if _tex.id == 0 {
+		return nil, errors.Wrapf(ErrGPUUpload, "unable to load texture %q", path)
+	}
 	tex := newTexture(_tex)
 	return tex, nil
 }
@@ -40,22 +62,21 @@ func LoadTexture(path string) (*Texture, error) {
 // LoadTextureFromImage reads the provided image and converts it into a
 // read-only texture.
 //
+// Pixels are stored with straight (non-premultiplied) alpha; *image.NRGBA
+// images are uploaded without conversion, and *image.RGBA images are converted
+// from premultiplied alpha.
+//
+// The returned error wraps ErrGPUUpload if the texture could not be uploaded to
+// the GPU.
+//
 // Note: a finalizer is registered to unload the texture.
 func LoadTextureFromImage(src image.Image) (*Texture, error) {
-	// Use fallback conversion for unknown image formats.
-	rgba, ok := src.(*image.RGBA)
-	if !ok {
-		return LoadTextureFromImage(fallbackRGBAImage(src))
-	}
-	// Use fallback conversion for subimages.
-	bounds := rgba.Bounds()
+	checkMainThread()
+	nrgba := contiguousNRGBAImage(src)
+	bounds := nrgba.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
-	const npixelBytes = 4 // RGBA
-	if rgba.Stride != npixelBytes*width {
-		return LoadTextureFromImage(fallbackRGBAImage(src))
-	}
 	// Create a read-only texture based on the pixels of the src image.
-	pix := unsafe.Pointer(&rgba.Pix[0])
+	pix := unsafe.Pointer(&nrgba.Pix[0])
 	_img := C.Image{
 		data:    pix,
 		width:   C.int(width),
@@ -64,24 +85,93 @@ func LoadTextureFromImage(src image.Image) (*Texture, error) {
 		format:  C.PIXELFORMAT_UNCOMPRESSED_R8G8B8A8,
 	}
 	_tex := C.LoadTextureFromImage(_img)
-	// TODO: figure out how to check error.
+	if _tex.id == 0 {
+		return nil, errors.Wrapf(ErrGPUUpload, "unable to load texture from %dx%d image", width, height)
+	}
 	tex := newTexture(_tex)
 	return tex, nil
 }
 
 // Width returns the width of the texture.
 func (tex *Texture) Width() int {
-	return int(tex._tex.width)
+	return tex.bounds.Dx()
+}
+
+// SubImage returns a sub-image of the texture, as defined by the rectangle r
+// relative to the top-left corner of the texture. The sub-image shares the GPU
+// texture of tex, and is clipped to the bounds of tex.
+//
+// Sub-images are lightweight views; e.g. frames of a sprite sheet. Sub-images
+// keep the texture of tex from being garbage collected, and must not be used
+// after tex has been freed. Freeing a sub-image has no effect.
+func (tex *Texture) SubImage(r image.Rectangle) *Texture {
+	parent := tex
+	if tex.parent != nil {
+		parent = tex.parent
+	}
+	sub := &Texture{
+		bounds: r.Add(tex.bounds.Min).Intersect(tex.bounds),
+		parent: parent,
+	}
+	return sub
+}
+
+// Free releases the GPU resources of the texture. The texture must not be used
+// after it has been freed. Freeing a texture more than once has no effect.
+//
+// Note: the texture is otherwise freed by Window.Display once it has been
+// garbage collected.
+func (tex *Texture) Free() {
+	checkMainThread()
+	if tex.parent != nil || tex._tex.id == 0 {
+		return
+	}
+	runtime.SetFinalizer(tex, nil)
+	// Draw pending draw operations using the texture before unloading it.
+	flushBatch()
+	C.UnloadTexture(tex._tex)
+	tex._tex = C.Texture2D{}
 }
 
 // Height returns the height of the texture.
 func (tex *Texture) Height() int {
-	return int(tex._tex.height)
+	return tex.bounds.Dy()
 }
 
+// SetFilter sets the filter used when scaling the texture.
+//
+// Note: the filter of sub-images is shared with the texture of the sub-image.
+func (tex *Texture) SetFilter(filter TextureFilter) {
+	checkMainThread()
+	C.SetTextureFilter(tex.raylibTexture(), C.int(filter))
+}
+
+// TextureFilter specifies the filter used when scaling textures.
+type TextureFilter int
+
+// Texture filters.
+const (
+	// No filter, just pixel approximation (default).
+	FilterPoint TextureFilter = C.TEXTURE_FILTER_POINT
+	// Linear filtering.
+	FilterBilinear TextureFilter = C.TEXTURE_FILTER_BILINEAR
+	// Trilinear filtering (linear with mipmaps).
+	FilterTrilinear TextureFilter = C.TEXTURE_FILTER_TRILINEAR
+	// Anisotropic filtering 4x.
+	FilterAnisotropic4x TextureFilter = C.TEXTURE_FILTER_ANISOTROPIC_4X
+	// Anisotropic filtering 8x.
+	FilterAnisotropic8x TextureFilter = C.TEXTURE_FILTER_ANISOTROPIC_8X
+	// Anisotropic filtering 16x.
+	FilterAnisotropic16x TextureFilter = C.TEXTURE_FILTER_ANISOTROPIC_16X
+)
+
 // Image converts the texture to a corresponding Go image.Image.
+//
+// Note: the pixels of the texture are stored with straight (non-premultiplied)
+// alpha, and are converted to the premultiplied alpha of *image.RGBA.
 func (tex *Texture) Image() image.Image {
-	_img := C.LoadImageFromTexture(tex._tex)
+	checkMainThread()
+	_img := C.LoadImageFromTexture(tex.raylibTexture())
 	var npixelBytes int
 	var getPixel func(data []byte) color.Color
 	defer C.UnloadImage(_img)
@@ -91,7 +181,7 @@ func (tex *Texture) Image() image.Image {
 		getPixel = func(data []byte) color.Color {
 			gray := data[0]
 			a := data[1]
-			return color.RGBA{
+			return color.NRGBA{
 				R: gray,
 				G: gray,
 				B: gray,
@@ -119,7 +209,7 @@ func (tex *Texture) Image() image.Image {
 			g := data[1]
 			b := data[2]
 			a := data[3]
-			return color.RGBA{
+			return color.NRGBA{
 				R: r,
 				G: g,
 				B: b,
@@ -131,14 +221,15 @@ func (tex *Texture) Image() image.Image {
 	}
 	width := int(_img.width)
 	height := int(_img.height)
-	bounds := image.Rect(0, 0, width, height)
-	dst := image.NewRGBA(bounds)
+	// Convert the pixels within the bounds of the texture.
+	bounds := tex.bounds
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
 	data := unsafe.Slice((*byte)(_img.data), width*height*npixelBytes)
-	for x := 0; x < width; x++ {
-		for y := 0; y < height; y++ {
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 			pos := (y*width + x) * npixelBytes
 			c := getPixel(data[pos : pos+npixelBytes])
-			dst.Set(x, y, c)
+			dst.Set(x-bounds.Min.X, y-bounds.Min.Y, c)
 		}
 	}
 	return dst
@@ -148,28 +239,129 @@ func (tex *Texture) Image() image.Image {
 
 // newTexture returns a new read-only texture.
 //
-// Note: a finalizer is registered to unload the texture.
+// Note: a finalizer is registered to unload the texture on the main thread.
 func newTexture(_tex C.Texture2D) *Texture {
 	tex := &Texture{
-		_tex: _tex,
+		_tex:   _tex,
+		bounds: image.Rect(0, 0, int(_tex.width), int(_tex.height)),
 	}
 	// Register finalizer to unload texture.
-	free := func(obj any) {
-		C.UnloadTexture(_tex)
+	free := func(tex *Texture) {
+		_tex := tex._tex
+		deferRelease(func() {
+			C.UnloadTexture(_tex)
+		})
 	}
 	runtime.SetFinalizer(tex, free)
 	return tex
 }
 
-// fallbackRGBAImage converts the provided image or subimage into an RGBA image.
-func fallbackRGBAImage(src image.Image) *image.RGBA {
+// raylibTexture returns the underlying raylib texture of the texture, which is
+// owned by the parent texture of sub-images. The texture ID is zero once the
+// texture (or parent texture) has been freed.
+func (tex *Texture) raylibTexture() C.Texture2D {
+	if tex.parent != nil {
+		return tex.parent._tex
+	}
+	return tex._tex
+}
+
+// clipRect translates the source rectangle sr, relative to the top-left corner
+// of the texture, to the underlying raylib texture, and clips it to the bounds
+// of the texture. The returned insets specify the amount clipped from the
+// top-left and bottom-right corners of sr, respectively.
+func (tex *Texture) clipRect(sr image.Rectangle) (r image.Rectangle, minInset, maxInset image.Point) {
+	sr = sr.Add(tex.bounds.Min)
+	r = sr.Intersect(tex.bounds)
+	if r.Empty() {
+		return r, image.Point{}, image.Point{}
+	}
+	return r, r.Min.Sub(sr.Min), sr.Max.Sub(r.Max)
+}
+
+// contiguousNRGBAImage returns the provided image as an NRGBA image with
+// contiguous pixel data. Pixels are converted to straight (non-premultiplied)
+// alpha, as expected by raylib; i.e. *image.RGBA images are converted from
+// premultiplied alpha, and fallback conversion is used for images other than
+// *image.RGBA and *image.NRGBA.
+func contiguousNRGBAImage(src image.Image) *image.NRGBA {
+	bounds := src.Bounds()
+	switch src := src.(type) {
+	case *image.NRGBA:
+		if src.Stride == npixelBytes*bounds.Dx() {
+			return src
+		}
+		// Copy the rows of subimages into contiguous pixel data.
+		dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		copyRows(dst.Pix, src.Pix, src.Stride, bounds)
+		return dst
+	case *image.RGBA:
+		dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		unpremultiply(dst.Pix, src)
+		return dst
+	default:
+		return fallbackNRGBAImage(src)
+	}
+}
+
+// npixelBytes specifies the number of bytes per pixel of 8-bit RGBA pixel data.
+const npixelBytes = 4
+
+// copyRows copies the rows of the given 8-bit RGBA pixel data of the specified
+// stride and bounds into the contiguous pixel data of dst.
+func copyRows(dst, pix []byte, stride int, bounds image.Rectangle) {
+	rowLen := npixelBytes * bounds.Dx()
+	for y := 0; y < bounds.Dy(); y++ {
+		copy(dst[y*rowLen:], pix[y*stride:y*stride+rowLen])
+	}
+}
+
+// unpremultiply converts the pixels of the provided RGBA image or subimage of
+// premultiplied alpha into the contiguous 8-bit RGBA pixel data of dst, of
+// straight alpha.
+func unpremultiply(dst []byte, src *image.RGBA) {
+	bounds := src.Bounds()
+	rowLen := npixelBytes * bounds.Dx()
+	for y := 0; y < bounds.Dy(); y++ {
+		row := src.Pix[y*src.Stride : y*src.Stride+rowLen]
+		dstRow := dst[y*rowLen : (y+1)*rowLen]
+		for i := 0; i < len(row); i += npixelBytes {
+			a := uint32(row[i+3])
+			switch a {
+			case 0:
+				// Fully transparent.
+				clear(dstRow[i : i+npixelBytes])
+			case 0xFF:
+				copy(dstRow[i:i+npixelBytes], row[i:i+npixelBytes])
+			default:
+				// ref: color.nrgbaModel (image/color/color.go)
+				dstRow[i+0] = uint8(uint32(row[i+0]) * 0xFFFF / a >> 8)
+				dstRow[i+1] = uint8(uint32(row[i+1]) * 0xFFFF / a >> 8)
+				dstRow[i+2] = uint8(uint32(row[i+2]) * 0xFFFF / a >> 8)
+				dstRow[i+3] = uint8(a)
+			}
+		}
+	}
+}
+
+// fallbackWarned tracks the image types for which fallback conversion has been
+// reported, so that images converted each frame (e.g. by MutableTexture.Update)
+// don't flood the log.
+var fallbackWarned = make(map[string]bool)
+
+// fallbackNRGBAImage converts the provided image or subimage into an NRGBA
+// image.
+func fallbackNRGBAImage(src image.Image) *image.NRGBA {
 	start := time.Now()
-	// Create a new RGBA image and draw the src image onto it.
+	// Create a new NRGBA image and draw the src image onto it.
 	bounds := src.Bounds()
 	dr := image.Rect(0, 0, bounds.Dx(), bounds.Dy())
-	dst := image.NewRGBA(dr)
+	dst := image.NewNRGBA(dr)
 	draw.Draw(dst, dr, src, bounds.Min, draw.Src)
-	clog.Warnf("fallback conversion for non-RGBA image (%T) finished in: %v", src, time.Since(start))
+	if typ := fmt.Sprintf("%T", src); !fallbackWarned[typ] {
+		fallbackWarned[typ] = true
+		clog.Warnf("fallback conversion for non-NRGBA image (%T) finished in: %v; further conversions of %T images are not reported", src, time.Since(start), src)
+	}
 	return dst
 }
 
@@ -0,0 +1,100 @@
+package window
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestTextureClipRect(t *testing.T) {
+	// 4x4 sub-image at (2, 2) of an 8x8 texture.
+	parent := &Texture{bounds: image.Rect(0, 0, 8, 8)}
+	sub := parent.SubImage(image.Rect(2, 2, 6, 6))
+	golden := []struct {
+		tex      *Texture
+		sr       image.Rectangle
+		want     image.Rectangle
+		minInset image.Point
+		maxInset image.Point
+	}{
+		// Within bounds.
+		{tex: parent, sr: image.Rect(0, 0, 8, 8), want: image.Rect(0, 0, 8, 8)},
+		{tex: parent, sr: image.Rect(1, 2, 3, 4), want: image.Rect(1, 2, 3, 4)},
+		{tex: sub, sr: image.Rect(0, 0, 4, 4), want: image.Rect(2, 2, 6, 6)},
+		{tex: sub, sr: image.Rect(1, 1, 2, 3), want: image.Rect(3, 3, 4, 5)},
+		// Clipped to bounds.
+		{tex: parent, sr: image.Rect(-2, -1, 4, 4), want: image.Rect(0, 0, 4, 4), minInset: image.Pt(2, 1)},
+		{tex: parent, sr: image.Rect(4, 4, 10, 9), want: image.Rect(4, 4, 8, 8), maxInset: image.Pt(2, 1)},
+		{tex: sub, sr: image.Rect(-1, -2, 5, 6), want: image.Rect(2, 2, 6, 6), minInset: image.Pt(1, 2), maxInset: image.Pt(1, 2)},
+		// Outside of bounds.
+		{tex: sub, sr: image.Rect(4, 4, 8, 8), want: image.Rectangle{}},
+	}
+	for _, g := range golden {
+		got, minInset, maxInset := g.tex.clipRect(g.sr)
+		if got != g.want {
+			t.Errorf("%v of %v: clipped rectangle mismatch; expected %v, got %v", g.sr, g.tex.bounds, g.want, got)
+		}
+		if minInset != g.minInset || maxInset != g.maxInset {
+			t.Errorf("%v of %v: insets mismatch; expected (%v, %v), got (%v, %v)", g.sr, g.tex.bounds, g.minInset, g.maxInset, minInset, maxInset)
+		}
+	}
+}
+
+func TestTextureSubImageOfFreed(t *testing.T) {
+	parent := &Texture{bounds: image.Rect(0, 0, 8, 8)}
+	parent._tex.id = 1
+	sub := parent.SubImage(image.Rect(2, 2, 6, 6)).SubImage(image.Rect(1, 1, 2, 2))
+	if id := sub.raylibTexture().id; id != 1 {
+		t.Errorf("texture ID mismatch; expected 1, got %d", id)
+	}
+	// Simulate Free of the parent texture.
+	parent._tex.id = 0
+	if id := sub.raylibTexture().id; id != 0 {
+		t.Errorf("texture ID of sub-image of freed texture mismatch; expected 0, got %d", id)
+	}
+}
+
+func TestContiguousNRGBAImage(t *testing.T) {
+	// 3x3 images of varying alpha, including fully transparent and opaque
+	// pixels.
+	var (
+		rgba  = image.NewRGBA(image.Rect(0, 0, 3, 3))
+		nrgba = image.NewNRGBA(image.Rect(0, 0, 3, 3))
+		gray  = image.NewGray(image.Rect(0, 0, 3, 3))
+	)
+	for i := range 9 {
+		x, y := i%3, i/3
+		c := color.NRGBA{R: uint8(i * 31), G: 0x80, B: 0xFF, A: uint8(i * 255 / 8)}
+		rgba.Set(x, y, c)
+		nrgba.Set(x, y, c)
+		gray.Set(x, y, c)
+	}
+	golden := []image.Image{
+		rgba,
+		rgba.SubImage(image.Rect(1, 1, 3, 3)),
+		nrgba,
+		nrgba.SubImage(image.Rect(1, 1, 3, 3)),
+		nrgba.SubImage(image.Rect(0, 1, 3, 2)),
+		gray.SubImage(image.Rect(1, 0, 3, 2)),
+	}
+	for _, src := range golden {
+		// Convert to straight alpha using the image/color package as reference.
+		bounds := src.Bounds()
+		want := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(want, want.Bounds(), src, bounds.Min, draw.Src)
+		got := contiguousNRGBAImage(src)
+		if got.Bounds().Size() != bounds.Size() {
+			t.Errorf("%T %v: dimensions mismatch; expected %v, got %v", src, bounds, bounds.Size(), got.Bounds().Size())
+			continue
+		}
+		if n := len(want.Pix); len(got.Pix) < n || !bytes.Equal(got.Pix[:n], want.Pix) {
+			t.Errorf("%T %v: pixels mismatch; expected %v, got %v", src, bounds, want.Pix, got.Pix)
+		}
+	}
+	// Contiguous NRGBA images are uploaded without conversion.
+	if got := contiguousNRGBAImage(nrgba); got != nrgba {
+		t.Errorf("contiguous NRGBA image copied; expected %p, got %p", nrgba, got)
+	}
+}
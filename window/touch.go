@@ -0,0 +1,211 @@
+package window
+
+// #include <raylib.h>
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// --- [ touch events ] --------------------------------------------------------
+
+// A TouchDown event is triggered when a touch point is placed on the screen.
+type TouchDown struct {
+	image.Point
+	// Touch point ID, stable until the touch point is lifted.
+	ID int
+}
+
+// A TouchMove event is triggered when a touch point moves on the screen.
+type TouchMove struct {
+	image.Point
+	// Position of the touch point at the previous frame.
+	From image.Point
+	// Touch point ID, stable until the touch point is lifted.
+	ID int
+}
+
+// A TouchUp event is triggered when a touch point is lifted from the screen.
+type TouchUp struct {
+	// Last known position of the touch point.
+	image.Point
+	// Touch point ID.
+	ID int
+}
+
+// touchPoint is a touch point on the screen.
+type touchPoint struct {
+	// Touch point ID.
+	id int
+	// Touch point position.
+	pos image.Point
+}
+
+// prevTouchPoints tracks the touch points on the screen at the previous frame.
+var prevTouchPoints []touchPoint
+
+// fillTouchEvents fills the event queue with touch and gesture events received
+// since last call to Window.Display.
+func fillTouchEvents() {
+	// Get current touch points.
+	n := int(C.GetTouchPointCount())
+	touchPoints := make([]touchPoint, 0, n)
+	for i := 0; i < n; i++ {
+		_pos := C.GetTouchPosition(C.int(i))
+		tp := touchPoint{
+			id:  int(C.GetTouchPointId(C.int(i))),
+			pos: image.Pt(int(_pos.x), int(_pos.y)),
+		}
+		touchPoints = append(touchPoints, tp)
+	}
+	// fill touch up events since last frame.
+	for _, prev := range prevTouchPoints {
+		if _, ok := findTouchPoint(touchPoints, prev.id); !ok {
+			event := TouchUp{
+				Point: prev.pos,
+				ID:    prev.id,
+			}
+			eventQueue.PushBack(event)
+		}
+	}
+	// fill touch down and move events since last frame.
+	for _, tp := range touchPoints {
+		prev, ok := findTouchPoint(prevTouchPoints, tp.id)
+		switch {
+		case !ok:
+			event := TouchDown{
+				Point: tp.pos,
+				ID:    tp.id,
+			}
+			eventQueue.PushBack(event)
+		case tp.pos != prev.pos:
+			event := TouchMove{
+				Point: tp.pos,
+				From:  prev.pos,
+				ID:    tp.id,
+			}
+			eventQueue.PushBack(event)
+		}
+	}
+	prevTouchPoints = touchPoints
+	// fill gesture events since last frame.
+	fillGestureEvents()
+}
+
+// findTouchPoint returns the touch point with the given ID, and a boolean
+// indicating success.
+func findTouchPoint(touchPoints []touchPoint, id int) (touchPoint, bool) {
+	for _, tp := range touchPoints {
+		if tp.id == id {
+			return tp, true
+		}
+	}
+	return touchPoint{}, false
+}
+
+// --- [ gesture events ] ------------------------------------------------------
+
+// Gesture is a bitfield of touch gestures.
+type Gesture uint
+
+// Touch gestures.
+const (
+	GestureTap        Gesture = C.GESTURE_TAP
+	GestureDoubleTap  Gesture = C.GESTURE_DOUBLETAP
+	GestureHold       Gesture = C.GESTURE_HOLD
+	GestureDrag       Gesture = C.GESTURE_DRAG
+	GestureSwipeRight Gesture = C.GESTURE_SWIPE_RIGHT
+	GestureSwipeLeft  Gesture = C.GESTURE_SWIPE_LEFT
+	GestureSwipeUp    Gesture = C.GESTURE_SWIPE_UP
+	GestureSwipeDown  Gesture = C.GESTURE_SWIPE_DOWN
+	GesturePinchIn    Gesture = C.GESTURE_PINCH_IN
+	GesturePinchOut   Gesture = C.GESTURE_PINCH_OUT
+
+	gestureFirst = GestureTap
+	gestureLast  = GesturePinchOut
+)
+
+// gestureNames maps from gesture to name.
+var gestureNames = map[Gesture]string{
+	GestureTap:        "tap",
+	GestureDoubleTap:  "double tap",
+	GestureHold:       "hold",
+	GestureDrag:       "drag",
+	GestureSwipeRight: "swipe right",
+	GestureSwipeLeft:  "swipe left",
+	GestureSwipeUp:    "swipe up",
+	GestureSwipeDown:  "swipe down",
+	GesturePinchIn:    "pinch in",
+	GesturePinchOut:   "pinch out",
+}
+
+// String returns a string representation of the gestures.
+func (gestures Gesture) String() string {
+	if gestures == 0 {
+		return ""
+	}
+	var names []string
+	for g := gestureFirst; g <= gestureLast; g <<= 1 {
+		if gestures&g != 0 {
+			names = append(names, gestureNames[g])
+		}
+	}
+	if unknown := gestures &^ (gestureLast<<1 - 1); unknown != 0 {
+		names = append(names, fmt.Sprintf("unknown gesture: %d", uint(unknown)))
+	}
+	return "[" + strings.Join(names, "+") + "]"
+}
+
+// A GestureDetect event is triggered each frame a gesture is detected.
+type GestureDetect struct {
+	// Position of the primary touch point.
+	image.Point
+	// Detected gesture.
+	Gesture Gesture
+	// Hold duration in seconds (GestureHold).
+	HoldDuration float64
+	// Drag vector and angle in degrees (GestureDrag and GestureSwipe*).
+	DragX, DragY, DragAngle float64
+	// Pinch vector and angle in degrees (GesturePinch*).
+	PinchX, PinchY, PinchAngle float64
+}
+
+// enabledGestures specifies the set of gestures reported as events.
+var enabledGestures Gesture
+
+// SetGestures sets the set of gestures for which GestureDetect events are
+// reported. No gesture events are reported by default.
+func (*Window) SetGestures(gestures Gesture) {
+	checkMainThread()
+	enabledGestures = gestures
+	C.SetGesturesEnabled(C.uint(gestures))
+}
+
+// fillGestureEvents fills the event queue with gesture events received since
+// last call to Window.Display.
+func fillGestureEvents() {
+	if enabledGestures == 0 {
+		return
+	}
+	gesture := Gesture(C.GetGestureDetected())
+	if gesture == 0 || enabledGestures&gesture == 0 {
+		return
+	}
+	_pos := C.GetTouchPosition(0)
+	_drag := C.GetGestureDragVector()
+	_pinch := C.GetGesturePinchVector()
+	event := GestureDetect{
+		Point:        image.Pt(int(_pos.x), int(_pos.y)),
+		Gesture:      gesture,
+		HoldDuration: float64(C.GetGestureHoldDuration()),
+		DragX:        float64(_drag.x),
+		DragY:        float64(_drag.y),
+		DragAngle:    float64(C.GetGestureDragAngle()),
+		PinchX:       float64(_pinch.x),
+		PinchY:       float64(_pinch.y),
+		PinchAngle:   float64(C.GetGesturePinchAngle()),
+	}
+	eventQueue.PushBack(event)
+}
@@ -4,7 +4,6 @@
 // subset of the features provided by the raylib library version 4.5.
 package window
 
-// #include <stdlib.h>
 // #include <raylib.h>
 //
 //#cgo LDFLAGS: -lraylib
@@ -15,46 +14,189 @@ import (
 	"image"
 	"image/color"
 	"runtime"
+	"unsafe"
 
 	"github.com/mewspring/wandi"
+	"github.com/pkg/errors"
 )
 
 func init() {
 	// Ensure that main goroutine runs on dedicated thread.
 	runtime.LockOSThread()
+	markMainThread()
 }
 
 // A Window represents a graphical window capable of handling draw operations
 // and window events. It implements the wandi.Window interface.
 type Window struct {
+	// Maximum window dimensions; or zero if unbounded.
+	maxSize image.Point
+	// Window is in borderless windowed mode.
+	borderless bool
+	// Window bounds and decoration before entering borderless windowed mode.
+	windowedBounds      image.Rectangle
+	windowedUndecorated bool
+	// Draw operations onto the window.
+	canvas
 }
 
-// Open opens a new window of the specified dimensions.
+// openWindow is the open window; or nil if no window is open.
+var openWindow *Window
+
+// Open opens a new window of the specified dimensions. The window may be
+// customized through the provided options.
+//
+// The returned error wraps ErrInit if the window could not be initialized.
 //
 // Note: the caller is responsible for invoking Close when finished using the
 // window.
-func Open(width, height int) (*Window, error) {
-	const title = "raylib"
-	C.SetTraceLogLevel(C.LOG_WARNING)
-	C.InitWindow(C.int(width), C.int(height), C.CString(title))
-	// TODO: figure out how to detect errors.
+func Open(width, height int, opts ...Option) (*Window, error) {
+	checkMainThread()
+	cfg := defaultOptions()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	logLevel = cfg.logLevel
+	C.SetTraceLogLevel(C.int(logLevel))
+	// Keep running while the window is minimized; otherwise raylib blocks within
+	// WindowShouldClose (in fillEventQueue) until the window is restored, thus
+	// stalling the application loop, minimize events and calls queued by Do.
+	C.SetConfigFlags(cfg.flags | C.FLAG_WINDOW_ALWAYS_RUN)
+	_title := cString(cfg.title)
+	defer freeCString(_title)
+	C.InitWindow(C.int(width), C.int(height), _title)
+	if !C.IsWindowReady() {
+		return nil, errors.Wrapf(ErrInit, "unable to open window of dimensions %dx%d", width, height)
+	}
+	C.SetTargetFPS(C.int(cfg.fps))
+	// Track initial window state, so that only changes are reported as events.
+	prevWindowState = getWindowState()
 	win := &Window{}
+	openWindow = win
 	return win, nil
 }
 
 // Close closes the window.
-func (*Window) Close() {
+func (win *Window) Close() {
+	checkMainThread()
 	C.CloseWindow()
+	if openWindow == win {
+		openWindow = nil
+	}
+	// The default font is unloaded by raylib when the window is closed.
+	defaultFont = nil
 }
 
 // SetTitle sets the title of the window.
 func (*Window) SetTitle(title string) {
-	C.SetWindowTitle(C.CString(title))
+	checkMainThread()
+	_title := cString(title)
+	defer freeCString(_title)
+	C.SetWindowTitle(_title)
+}
+
+// SetFullscreen enables or disables fullscreen mode of the window depending on
+// the value of fullscreen.
+func (*Window) SetFullscreen(fullscreen bool) {
+	checkMainThread()
+	if bool(C.IsWindowFullscreen()) != fullscreen {
+		C.ToggleFullscreen()
+	}
+}
+
+// SetBorderless enables or disables borderless windowed mode depending on the
+// value of borderless. In borderless windowed mode, the window is undecorated
+// and covers the entire monitor without changing its video mode.
+func (win *Window) SetBorderless(borderless bool) {
+	checkMainThread()
+	if win.borderless == borderless {
+		return
+	}
+	if borderless {
+		// Record windowed mode state, to be restored when leaving borderless
+		// windowed mode.
+		size := image.Pt(int(C.GetScreenWidth()), int(C.GetScreenHeight()))
+		win.windowedBounds = image.Rectangle{Min: win.Pos(), Max: win.Pos().Add(size)}
+		win.windowedUndecorated = bool(C.IsWindowState(C.FLAG_WINDOW_UNDECORATED))
+		monitor := C.GetCurrentMonitor()
+		_pos := C.GetMonitorPosition(monitor)
+		C.SetWindowState(C.FLAG_WINDOW_UNDECORATED)
+		C.SetWindowPosition(C.int(_pos.x), C.int(_pos.y))
+		C.SetWindowSize(C.GetMonitorWidth(monitor), C.GetMonitorHeight(monitor))
+	} else {
+		if !win.windowedUndecorated {
+			C.ClearWindowState(C.FLAG_WINDOW_UNDECORATED)
+		}
+		bounds := win.windowedBounds
+		C.SetWindowSize(C.int(bounds.Dx()), C.int(bounds.Dy()))
+		C.SetWindowPosition(C.int(bounds.Min.X), C.int(bounds.Min.Y))
+	}
+	win.borderless = borderless
+}
+
+// SetMinSize sets the minimum dimensions of the window.
+//
+// Note: only applicable to resizable windows.
+func (*Window) SetMinSize(width, height int) {
+	checkMainThread()
+	C.SetWindowMinSize(C.int(width), C.int(height))
+}
+
+// SetMaxSize sets the maximum dimensions of the window. A width or height of 0
+// leaves the given dimension unbounded.
+//
+// Note: the maximum dimensions are emulated, as raylib 4.5 provides no
+// SetWindowMaxSize. The window is resized to fit within the maximum dimensions
+// immediately, and again by Window.Display when the window has been resized
+// beyond them.
+func (win *Window) SetMaxSize(width, height int) {
+	checkMainThread()
+	win.maxSize = image.Pt(width, height)
+	win.clampSize()
+}
+
+// Pos returns the position of the top-left corner of the window on the screen.
+func (*Window) Pos() image.Point {
+	checkMainThread()
+	_pos := C.GetWindowPosition()
+	pos := image.Pt(int(_pos.x), int(_pos.y))
+	return pos
+}
+
+// SetPos sets the position of the top-left corner of the window on the screen.
+func (*Window) SetPos(pos image.Point) {
+	checkMainThread()
+	C.SetWindowPosition(C.int(pos.X), C.int(pos.Y))
+}
+
+// SetOpacity sets the opacity of the window, in the range [0, 1].
+func (*Window) SetOpacity(opacity float64) {
+	checkMainThread()
+	C.SetWindowOpacity(C.float(opacity))
+}
+
+// SetIcon sets the icon of the window.
+func (*Window) SetIcon(icon image.Image) {
+	checkMainThread()
+	nrgba := contiguousNRGBAImage(icon)
+	bounds := nrgba.Bounds()
+	if bounds.Empty() {
+		return
+	}
+	_img := C.Image{
+		data:    unsafe.Pointer(&nrgba.Pix[0]),
+		width:   C.int(bounds.Dx()),
+		height:  C.int(bounds.Dy()),
+		mipmaps: 1,
+		format:  C.PIXELFORMAT_UNCOMPRESSED_R8G8B8A8,
+	}
+	C.SetWindowIcon(_img)
 }
 
 // ShowCursor displays or hides the mouse cursor depending on the value of
 // visible. It is visible by default.
 func (*Window) ShowCursor(visible bool) {
+	checkMainThread()
 	if visible {
 		C.ShowCursor()
 	} else {
@@ -64,6 +206,7 @@ func (*Window) ShowCursor(visible bool) {
 
 // Width returns the width of the window.
 func (*Window) Width() int {
+	checkMainThread()
 	// TODO: double-check that renderer width corresponds to window width.
 	width := int(C.GetRenderWidth())
 	return width
@@ -71,6 +214,7 @@ func (*Window) Width() int {
 
 // Height returns the height of the window.
 func (*Window) Height() int {
+	checkMainThread()
 	// TODO: double-check that renderer width corresponds to window width.
 	height := int(C.GetRenderHeight())
 	return height
@@ -79,44 +223,57 @@ func (*Window) Height() int {
 // Draw draws the entire src image onto the window starting at the destination
 // point dp.
 func (win *Window) Draw(dp image.Point, src wandi.Image) error {
-	sr := image.Rect(0, 0, src.Width(), src.Height()) // TODO: add support for subimages; bounds.Min instead of (0,0).
+	sr := image.Rect(0, 0, src.Width(), src.Height())
 	return win.DrawRect(dp, src, sr)
 }
 
 // DrawRect draws a subset of the src image, as defined by the source rectangle
 // sr, onto the window starting at the destination point dp.
 func (win *Window) DrawRect(dp image.Point, src wandi.Image, sr image.Rectangle) error {
-	switch src := src.(type) {
-	case *Texture:
-		_sr := raylibRectangle(sr)
-		_dp := vector2FromPoint(dp)
-		_tint := raylibColor(color.White)
-		C.DrawTextureRec(src._tex, _sr, _dp, _tint)
-	case *Text:
-		_dp := vector2FromPoint(dp)
-		C.DrawTextEx(src.font._font, src._str, _dp, C.float(src.fontSize), defaultSpacing, raylibColor(src.c))
-	default:
-		panic(fmt.Errorf("support for image format %T not yet implemented", src))
-	}
-	return nil
+	win.bind()
+	return drawRect(dp, src, sr)
 }
 
-// Clear clears the entire window with the given color.
+// DrawEx draws the src image onto the window starting at the destination point
+// dp, as transformed and tinted by the given draw options.
+func (win *Window) DrawEx(dp image.Point, src wandi.Image, opts *DrawOptions) error {
+	win.bind()
+	return drawEx(dp, src, opts)
+}
+
+// Clear clears the entire window with the given color. Only the current clip
+// rectangle is cleared if set.
 func (win *Window) Clear(c color.Color) {
+	win.bind()
 	C.ClearBackground(raylibColor(c))
 }
 
 // Display displays what has been rendered so far to the window.
-func (*Window) Display() {
+func (win *Window) Display() {
+	checkMainThread()
+	// finish drawing to offscreen render targets, if any.
+	bindTarget(win._rt)
 	// draw everything + SwapScreenBuffer + PollInputEvents.
 	C.EndDrawing()
+	// release GPU resources of objects garbage collected since the last frame.
+	releaseResources()
 	C.BeginDrawing()
-	// populate the input event queue.
+	// Note: BeginDrawing resets the view transform.
+	curCamera = cameraState{}
+	if C.IsWindowResized() {
+		win.clampSize()
+	}
+	// call functions queued by Do and DoAsync.
+	handleCalls()
+	// reload watched shaders with changed source files.
+	reloadShaders()
+	// populate the input and window event queue.
 	fillEventQueue()
 }
 
 // CursorPos returns the current cursor position within the given window.
 func (*Window) CursorPos() image.Point {
+	checkMainThread()
 	_pt := C.GetMousePosition()
 	pt := image.Pt(int(_pt.x), int(_pt.y))
 	return pt
@@ -124,18 +281,81 @@ func (*Window) CursorPos() image.Point {
 
 // SetCursorPos sets the position of the cursor in the given window.
 func (*Window) SetCursorPos(pt image.Point) {
+	checkMainThread()
 	// TODO: double-check that mouse position is within window (i.e. (0,0) is
 	// top-left corner of window).
 	C.SetMousePosition(C.int(pt.X), C.int(pt.Y))
 }
 
-// DrawFPS draws the current FPS at the specified point.
+// DrawFPS draws the current FPS at the specified point of the window.
 func DrawFPS(pt image.Point) {
+	checkMainThread()
+	// draw onto the window using the draw state of the window, rather than onto
+	// the last drawn render target.
+	if openWindow != nil {
+		openWindow.bind()
+	}
 	C.DrawFPS(C.int(pt.X), C.int(pt.Y))
 }
 
 // ### [ Helper functions ] ####################################################
 
+// drawRect draws a subset of the src image, as defined by the source rectangle
+// sr, onto the active draw target starting at the destination point dp.
+func drawRect(dp image.Point, src wandi.Image, sr image.Rectangle) error {
+	if mtex, ok := src.(*MutableTexture); ok {
+		src = mtex.Texture
+	}
+	switch src := src.(type) {
+	case *Texture:
+		// Translate source rectangle to the bounds of sub-images, and offset the
+		// destination point by the amount clipped from the top-left corner of the
+		// source rectangle.
+		r, inset, _ := src.clipRect(sr)
+		if r.Empty() {
+			return nil
+		}
+		_sr := raylibRectangle(r)
+		_dp := vector2FromPoint(dp.Add(inset))
+		_tint := raylibColor(color.White)
+		C.DrawTextureRec(src.raylibTexture(), _sr, _dp, _tint)
+	case *RenderTarget:
+		if src._rt.id == activeTarget.id {
+			return errors.New("unable to draw render target onto itself")
+		}
+		// Flip source rectangle vertically, as the texture of render targets is
+		// stored bottom-up.
+		_sr := raylibRectangle(sr)
+		_sr.y = C.float(src.Height() - sr.Max.Y)
+		_sr.height = -_sr.height
+		_dp := vector2FromPoint(dp)
+		_tint := raylibColor(color.White)
+		C.DrawTextureRec(src._rt.texture, _sr, _dp, _tint)
+	case *Text:
+		drawText(src, vector2FromPoint(dp), C.Vector2{}, 0, 1, src.c)
+	case *RichText:
+		drawRichText(src, vector2FromPoint(dp), C.Vector2{}, 0, 1, color.White)
+	default:
+		panic(fmt.Errorf("support for image format %T not yet implemented", src))
+	}
+	return nil
+}
+
+// clampSize resizes the window to fit within its maximum dimensions.
+func (win *Window) clampSize() {
+	width, height := int(C.GetScreenWidth()), int(C.GetScreenHeight())
+	newWidth, newHeight := width, height
+	if win.maxSize.X > 0 && newWidth > win.maxSize.X {
+		newWidth = win.maxSize.X
+	}
+	if win.maxSize.Y > 0 && newHeight > win.maxSize.Y {
+		newHeight = win.maxSize.Y
+	}
+	if newWidth != width || newHeight != height {
+		C.SetWindowSize(C.int(newWidth), C.int(newHeight))
+	}
+}
+
 // raylibRectangle converts the given Go rectangle to the corresponding raylib
 // rectangle.
 func raylibRectangle(rect image.Rectangle) C.Rectangle {
@@ -167,5 +387,8 @@ func raylibColor(c color.Color) C.Color {
 	}
 }
 
-// Ensure that Window implements wandi.Window.
-var _ wandi.Window = (*Window)(nil)
+// Ensure that Window implements wandi.Window and Canvas.
+var (
+	_ wandi.Window = (*Window)(nil)
+	_ Canvas       = (*Window)(nil)
+)